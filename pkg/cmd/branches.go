@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"context"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/MaikelVeen/branch/pkg/git"
+	"github.com/MaikelVeen/branch/pkg/jira"
+
+	cfg "github.com/MaikelVeen/branch/pkg/config"
+)
+
+// BranchInfo is a branch annotated with the Jira issue its name refers to.
+type BranchInfo struct {
+	Branch             string    `json:"branch"`
+	Remote             bool      `json:"remote,omitempty"`
+	Current            bool      `json:"current,omitempty"`
+	Key                string    `json:"key,omitempty"`
+	Summary            string    `json:"summary,omitempty"`
+	Status             string    `json:"status,omitempty"`
+	StatusCategory     string    `json:"statusCategory,omitempty"`
+	StatusCategoryName string    `json:"statusCategoryName,omitempty"`
+	Assignee           string    `json:"assignee,omitempty"`
+	LastCommit         time.Time `json:"lastCommit"`
+	Merged             bool      `json:"merged"`
+}
+
+// Done reports whether the issue of the branch is in the done status category.
+func (b *BranchInfo) Done() bool {
+	return b.StatusCategory == jira.StatusCategoryDone
+}
+
+// NewBranchInfos annotates `refs` with the matching `issues`, whether they are
+// in `merged` and whether they are the `current` branch.
+func NewBranchInfos(refs []git.BranchRef, issues []jira.Issue, merged []string, current string) []BranchInfo {
+	byKey := make(map[string]*jira.Issue, len(issues))
+	for i := range issues {
+		byKey[issues[i].Key] = &issues[i]
+	}
+
+	branches := make([]BranchInfo, 0, len(refs))
+	for _, ref := range refs {
+		info := BranchInfo{
+			Branch:     ref.Name,
+			Remote:     ref.Remote(),
+			Current:    !ref.Remote() && ref.Name == current,
+			Key:        jira.ExtractIssueKey(ref.Name),
+			LastCommit: ref.CommitDate,
+			Merged:     slices.Contains(merged, ref.Name),
+		}
+
+		if issue, ok := byKey[info.Key]; ok {
+			info.Summary = issue.Fields.Summary
+			info.Status = issue.Fields.Status.Name
+			info.StatusCategory = issue.Fields.Status.StatusCategory.Key
+			info.StatusCategoryName = issue.Fields.Status.StatusCategory.Name
+			if issue.Fields.Assignee != nil {
+				info.Assignee = issue.Fields.Assignee.DisplayName
+			}
+		}
+
+		branches = append(branches, info)
+	}
+
+	return branches
+}
+
+// FilterBranchesByStatus returns the branches whose issue has one of `statuses`,
+// compared case insensitively against the status name and the name and key of
+// the status category.
+func FilterBranchesByStatus(branches []BranchInfo, statuses []string) []BranchInfo {
+	if len(statuses) == 0 {
+		return branches
+	}
+
+	var filtered []BranchInfo
+	for _, b := range branches {
+		for _, s := range statuses {
+			if b.Key != "" && (strings.EqualFold(b.Status, s) ||
+				strings.EqualFold(b.StatusCategoryName, s) ||
+				strings.EqualFold(b.StatusCategory, s)) {
+				filtered = append(filtered, b)
+				break
+			}
+		}
+	}
+
+	return filtered
+}
+
+// collectBranches returns the local branches, and the remote-tracking branches
+// when `remote` is true, annotated with their Jira issues. When `client` is nil
+// the branches are not annotated.
+func collectBranches(
+	ctx context.Context,
+	g *git.Commander,
+	client *jira.Client,
+	base string,
+	remote bool,
+) ([]BranchInfo, error) {
+	refs, err := g.LocalBranches(exec.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	base = g.BaseRef(exec.Command, base, git.DefaultRemote)
+
+	merged, err := g.MergedBranches(exec.Command, base, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if remote {
+		remoteRefs, err := g.RemoteBranches(exec.Command)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, remoteRefs...)
+
+		remoteMerged, err := g.MergedBranches(exec.Command, base, true)
+		if err != nil {
+			return nil, err
+		}
+		merged = append(merged, remoteMerged...)
+	}
+
+	// HEAD is detached when this fails, in which case no branch is current.
+	current, _ := g.ShortSymbolicRef(exec.Command)
+
+	var issues []jira.Issue
+	if client != nil {
+		if issues, err = fetchBranchIssues(ctx, client, refs); err != nil {
+			return nil, err
+		}
+	}
+
+	return NewBranchInfos(refs, issues, merged, current), nil
+}
+
+// fetchBranchIssues fetches the issues referred to by the names of `refs` in as few requests as possible.
+func fetchBranchIssues(ctx context.Context, client *jira.Client, refs []git.BranchRef) ([]jira.Issue, error) {
+	var keys []string
+	for _, ref := range refs {
+		if key := jira.ExtractIssueKey(ref.Name); key != "" && !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	return client.Search.ByKeys(ctx, keys, &jira.SearchOptions{
+		Fields:     []string{"summary", "status", "assignee"},
+		MaxResults: len(keys),
+	})
+}
+
+// defaultBaseBranch returns the default branch of the remote. When it can not be
+// determined, for example in a repository without a remote, cfg.DefaultBaseBranch
+// is returned with the error.
+func defaultBaseBranch(g *git.Commander) (string, error) {
+	branch, err := g.DefaultBranch(exec.Command, git.DefaultRemote)
+	if err != nil {
+		return cfg.DefaultBaseBranch, err
+	}
+
+	return branch, nil
+}
@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/MaikelVeen/branch/pkg/cmd/jira/auth"
+	"github.com/MaikelVeen/branch/pkg/git"
+	"github.com/charmbracelet/huh"
+	"github.com/lmittmann/tint"
+	"github.com/spf13/cobra"
+
+	cfg "github.com/MaikelVeen/branch/pkg/config"
+)
+
+const (
+	ArgDryRun = "dry-run"
+	ArgForce  = "force"
+	ArgYes    = "yes"
+	ArgRemote = "remote"
+)
+
+type CleanCommand struct {
+	Command *cobra.Command
+
+	logger *slog.Logger
+	git    *git.Commander
+
+	BaseBranch string
+	DryRun     bool
+	Force      bool
+	Yes        bool
+	Remote     bool
+}
+
+func NewCleanCommand() *CleanCommand {
+	cc := &CleanCommand{
+		logger: slog.New(
+			tint.NewHandler(os.Stdout, &tint.Options{
+				Level:      slog.LevelInfo,
+				TimeFormat: time.Kitchen,
+			}),
+		),
+		git: git.NewCommander(),
+	}
+
+	cc.Command = &cobra.Command{
+		Use:   "clean",
+		Short: "Deletes branches whose issues are done or that are merged into the base branch",
+		Long: "Deletes local branches whose Jira issues are in the done status category or that are " +
+			"merged into the base branch. The current branch, the base branch and the branches in " +
+			cfg.KeyCleanProtected + " are never deleted.\n\n" +
+			"With --remote, remote-tracking branches are deleted as well. This only removes the local " +
+			"refs, the branches on the remote are not touched.",
+		Args: cobra.NoArgs,
+		RunE: cc.Execute,
+	}
+
+	flagset := cc.Command.Flags()
+	flagset.StringVarP(
+		&cc.BaseBranch,
+		ArgBase,
+		ArgBaseShort,
+		"",
+		"Base branch to check whether branches are merged, defaults to the default branch of the remote",
+	)
+	flagset.BoolVar(&cc.DryRun, ArgDryRun, false, "Only list the branches that would be deleted")
+	flagset.BoolVar(&cc.Force, ArgForce, false, "Also delete branches that are not merged")
+	flagset.BoolVar(&cc.Yes, ArgYes, false, "Delete without confirmation")
+	flagset.BoolVar(&cc.Remote, ArgRemote, false, "Also delete remote-tracking branches")
+
+	return cc
+}
+
+func (c *CleanCommand) Execute(cmd *cobra.Command, _ []string) error {
+	config, err := cfg.Load()
+	if err != nil {
+		return err
+	}
+
+	// Without Jira only merged branches can be cleaned.
+	client, err := auth.NewClientFromContext(cmd.Context())
+	if err != nil {
+		c.logger.Warn("not authenticated with Jira, only merged branches are cleaned")
+		client = nil
+	}
+
+	if c.BaseBranch == "" {
+		if c.BaseBranch, err = defaultBaseBranch(c.git); err != nil {
+			c.logger.Warn(fmt.Sprintf("%s, using %s", err, c.BaseBranch))
+		}
+	}
+
+	branches, err := collectBranches(cmd.Context(), c.git, client, c.BaseBranch, c.Remote)
+	if err != nil {
+		return describeBranchError(err, c.BaseBranch)
+	}
+
+	protected := append([]string{c.BaseBranch}, config.ProtectedBranches()...)
+	candidates := CleanCandidates(branches, protected)
+	if len(candidates) == 0 {
+		c.logger.Info("nothing to clean")
+		return nil
+	}
+
+	if c.DryRun {
+		for _, b := range candidates {
+			c.logger.Info(fmt.Sprintf("would delete %s (%s)", b.Branch, CleanReason(&b)))
+		}
+		return nil
+	}
+
+	selected := candidates
+	confirmed := false
+	if !c.Yes {
+		if selected, err = confirmClean(candidates); err != nil {
+			return err
+		}
+		confirmed = true
+	}
+
+	var failed int
+	for _, b := range selected {
+		if err = c.git.DeleteBranch(exec.Command, b.Branch, ForceDelete(&b, c.Force, confirmed), b.Remote); err != nil {
+			failed++
+			c.logger.Warn(fmt.Sprintf("failed to delete %s: %s", b.Branch, gitErrorMessage(err)))
+			continue
+		}
+		c.logger.Info(fmt.Sprintf("deleted %s (%s)", b.Branch, CleanReason(&b)))
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d branches could not be deleted, use --%s to delete unmerged branches", failed, ArgForce)
+	}
+
+	return nil
+}
+
+// confirmClean asks the user which of `candidates` to delete, all are selected by default.
+func confirmClean(candidates []BranchInfo) ([]BranchInfo, error) {
+	options := make([]huh.Option[int], 0, len(candidates))
+	for i, b := range candidates {
+		label := fmt.Sprintf("%s (%s)", b.Branch, CleanReason(&b))
+		options = append(options, huh.NewOption(label, i).Selected(true))
+	}
+
+	var indices []int
+	if err := huh.NewMultiSelect[int]().
+		Title("Delete these branches?").
+		Description("Press x to toggle a branch, / to filter and enter to confirm").
+		Options(options...).
+		Filterable(true).
+		Value(&indices).
+		Run(); err != nil {
+		return nil, err
+	}
+
+	selected := make([]BranchInfo, 0, len(indices))
+	for _, i := range indices {
+		selected = append(selected, candidates[i])
+	}
+
+	return selected, nil
+}
+
+// CleanCandidates returns the branches that can be cleaned: branches whose issue is
+// done or that are merged, except the current branch and `protected` branches.
+func CleanCandidates(branches []BranchInfo, protected []string) []BranchInfo {
+	var candidates []BranchInfo
+	for _, b := range branches {
+		if b.Current || IsProtectedBranch(b, protected) {
+			continue
+		}
+
+		if b.Done() || b.Merged {
+			candidates = append(candidates, b)
+		}
+	}
+
+	return candidates
+}
+
+// IsProtectedBranch reports whether `b` matches one of the names or glob patterns in
+// `protected`. Remote-tracking branches are matched without the remote name.
+func IsProtectedBranch(b BranchInfo, protected []string) bool {
+	name := b.Branch
+	if b.Remote {
+		if _, short, ok := strings.Cut(name, "/"); ok {
+			name = short
+		}
+	}
+
+	for _, pattern := range protected {
+		if pattern == name {
+			return true
+		}
+
+		if ok, err := path.Match(pattern, name); err == nil && ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ForceDelete reports whether `b` is deleted even when it is not merged: with
+// `force`, or when the user `confirmed` deleting the branch of a done issue.
+func ForceDelete(b *BranchInfo, force, confirmed bool) bool {
+	return force || (confirmed && b.Done())
+}
+
+// CleanReason describes why `b` can be cleaned.
+func CleanReason(b *BranchInfo) string {
+	var reasons []string
+	if b.Done() {
+		reasons = append(reasons, fmt.Sprintf("%s is %s", b.Key, b.Status))
+	}
+	if b.Merged {
+		reasons = append(reasons, "merged")
+	}
+
+	return strings.Join(reasons, ", ")
+}
+
+// gitErrorMessage returns the message git printed for a failed command.
+func gitErrorMessage(err error) string {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+		return strings.TrimSpace(string(exitErr.Stderr))
+	}
+
+	return err.Error()
+}
@@ -0,0 +1,71 @@
+package cmd_test
+
+import (
+	"testing"
+
+	"github.com/MaikelVeen/branch/pkg/cmd"
+	"github.com/MaikelVeen/branch/pkg/jira"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCleanCandidates(t *testing.T) {
+	t.Parallel()
+
+	done := jira.StatusCategoryDone
+	branches := []cmd.BranchInfo{
+		{Branch: "main", Merged: true},
+		{Branch: "release/1.0", Merged: true},
+		{Branch: "ABC-1", Key: "ABC-1", Status: "Done", StatusCategory: done},
+		{Branch: "ABC-2", Key: "ABC-2", Status: "In Progress", Merged: true},
+		{Branch: "ABC-3", Key: "ABC-3", Status: "In Progress"},
+		{Branch: "ABC-4", Key: "ABC-4", Status: "Done", StatusCategory: done, Current: true},
+		{Branch: "origin/main", Remote: true, Merged: true},
+		{Branch: "origin/ABC-1", Key: "ABC-1", Status: "Done", StatusCategory: done, Remote: true},
+	}
+
+	var names []string
+	for _, b := range cmd.CleanCandidates(branches, []string{"main", "release/*"}) {
+		names = append(names, b.Branch)
+	}
+
+	assert.Equal(t, []string{"ABC-1", "ABC-2", "origin/ABC-1"}, names)
+}
+
+func TestIsProtectedBranch(t *testing.T) {
+	t.Parallel()
+
+	protected := []string{"main", "release/*"}
+
+	assert.True(t, cmd.IsProtectedBranch(cmd.BranchInfo{Branch: "main"}, protected))
+	assert.True(t, cmd.IsProtectedBranch(cmd.BranchInfo{Branch: "release/2.1"}, protected))
+	assert.True(t, cmd.IsProtectedBranch(cmd.BranchInfo{Branch: "upstream/release/2.1", Remote: true}, protected))
+	assert.False(t, cmd.IsProtectedBranch(cmd.BranchInfo{Branch: "release/2.1/hotfix"}, protected))
+	assert.False(t, cmd.IsProtectedBranch(cmd.BranchInfo{Branch: "origin"}, protected))
+	assert.False(t, cmd.IsProtectedBranch(cmd.BranchInfo{Branch: "feature/main"}, protected))
+}
+
+func TestCleanReason(t *testing.T) {
+	t.Parallel()
+
+	b := &cmd.BranchInfo{Key: "ABC-1", Status: "Closed", StatusCategory: jira.StatusCategoryDone, Merged: true}
+	assert.Equal(t, "ABC-1 is Closed, merged", cmd.CleanReason(b))
+
+	b.StatusCategory = jira.StatusCategoryInProgress
+	assert.Equal(t, "merged", cmd.CleanReason(b))
+}
+
+func TestForceDelete(t *testing.T) {
+	t.Parallel()
+
+	done := &cmd.BranchInfo{Branch: "ABC-1", Key: "ABC-1", Status: "Done", StatusCategory: jira.StatusCategoryDone}
+	merged := &cmd.BranchInfo{Branch: "ABC-2", Key: "ABC-2", Status: "In Progress", Merged: true}
+
+	// Unmerged branches of done issues are candidates, confirming them deletes them with -D.
+	assert.Len(t, cmd.CleanCandidates([]cmd.BranchInfo{*done}, nil), 1)
+	assert.True(t, cmd.ForceDelete(done, false, true))
+	assert.False(t, cmd.ForceDelete(done, false, false), "without confirmation --force is needed")
+	assert.True(t, cmd.ForceDelete(done, true, false))
+
+	assert.False(t, cmd.ForceDelete(merged, false, true), "merged branches are deleted with -d")
+	assert.True(t, cmd.ForceDelete(merged, true, true))
+}
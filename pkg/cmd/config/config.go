@@ -24,5 +24,8 @@ func NewCommand() *Command {
 
 	cmd.Command.AddCommand(NewSetCommand().Command)
 	cmd.Command.AddCommand(NewGetCommand().Command)
+	cmd.Command.AddCommand(NewListCommand().Command)
+	cmd.Command.AddCommand(NewUnsetCommand().Command)
+	cmd.Command.AddCommand(NewEditCommand().Command)
 	return cmd
 }
@@ -0,0 +1,158 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	cfg "github.com/MaikelVeen/branch/pkg/config"
+	"github.com/charmbracelet/huh"
+	"github.com/lmittmann/tint"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// defaultEditor is used when neither $EDITOR nor $VISUAL is set.
+	defaultEditor = "vi"
+)
+
+// EditCommand opens the user configuration in an editor.
+type EditCommand struct {
+	Command *cobra.Command
+	logger  *slog.Logger
+
+	// Edit opens `file` in an editor and returns when the editor is closed.
+	Edit func(file string) error
+	// Retry asks whether to edit an invalid configuration again.
+	Retry func(err error) (bool, error)
+}
+
+func NewEditCommand() *EditCommand {
+	cmd := &EditCommand{
+		logger: slog.New(
+			tint.NewHandler(os.Stdout, &tint.Options{
+				Level:      slog.LevelInfo,
+				TimeFormat: time.Kitchen,
+			}),
+		),
+		Edit:  runEditor,
+		Retry: confirmRetry,
+	}
+
+	cmd.Command = &cobra.Command{
+		Use:   "edit",
+		Short: "Edit the user configuration in $EDITOR",
+		Long: "Opens the user configuration in $EDITOR. The edited configuration is validated " +
+			"before it is saved, an invalid configuration is never saved.",
+		Args: cobra.NoArgs,
+		RunE: cmd.Execute,
+	}
+
+	return cmd
+}
+
+func (c *EditCommand) Execute(_ *cobra.Command, _ []string) error {
+	file, err := cfg.UserConfigFile()
+	if err != nil {
+		return err
+	}
+
+	original, err := os.ReadFile(file)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	// The configuration is edited in a copy, so an invalid configuration is never saved.
+	if err = os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(file), "config-*.yaml")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(original); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	for {
+		if err = c.Edit(tmp.Name()); err != nil {
+			return fmt.Errorf("failed to run the editor: %w", err)
+		}
+
+		invalid := cfg.ValidateFile(tmp.Name())
+		if invalid == nil {
+			break
+		}
+
+		// The changes are discarded as well when the prompt can not be shown.
+		if retry, err := c.Retry(invalid); err != nil || !retry {
+			return fmt.Errorf("invalid configuration, the changes are discarded: %w", invalid)
+		}
+	}
+
+	edited, err := os.ReadFile(tmp.Name())
+	if err != nil {
+		return err
+	}
+
+	if bytes.Equal(edited, original) {
+		c.logger.Info("no changes")
+		return nil
+	}
+
+	if err = os.WriteFile(file, edited, 0600); err != nil {
+		return err
+	}
+
+	c.logger.Info(fmt.Sprintf("saved %s", file))
+	return nil
+}
+
+// runEditor opens `file` in $EDITOR, $VISUAL or vi. The editor can include
+// arguments, such as `code --wait`.
+func runEditor(file string) error {
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = os.Getenv("VISUAL")
+	}
+	if editor == "" {
+		editor = defaultEditor
+	}
+
+	args := strings.Fields(editor)
+	cmd := exec.Command(args[0], append(args[1:], file)...) //nolint:gosec // The editor is chosen by the user.
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
+
+// confirmRetry shows `err` and asks whether to edit the configuration again.
+func confirmRetry(err error) (bool, error) {
+	var retry bool
+	if err := huh.NewConfirm().
+		Title("The configuration is invalid, edit it again?").
+		Description(err.Error()).
+		Affirmative("Edit").
+		Negative("Discard").
+		Value(&retry).
+		Run(); err != nil {
+		return false, err
+	}
+
+	return retry, nil
+}
@@ -0,0 +1,144 @@
+package config_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MaikelVeen/branch/pkg/cmd/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cfg "github.com/MaikelVeen/branch/pkg/config"
+)
+
+// initUserConfig initializes the configuration with a user configuration file
+// holding `content`, outside of any repository. Returns the path of the file.
+func initUserConfig(t *testing.T, content string) string {
+	t.Helper()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	file := filepath.Join(home, ".config", "branch", "config.yaml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(file), 0o755))
+	require.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	_, err = cfg.Init()
+	require.NoError(t, err)
+
+	return file
+}
+
+// editWith returns an Edit hook that writes each of `contents` in turn.
+func editWith(t *testing.T, contents ...string) (func(file string) error, *int) {
+	t.Helper()
+
+	var calls int
+	return func(file string) error {
+		require.Less(t, calls, len(contents), "unexpected edit")
+		content := contents[calls]
+		calls++
+		return os.WriteFile(file, []byte(content), 0o600)
+	}, &calls
+}
+
+//nolint:paralleltest // Changes the environment and the global configuration.
+func TestEditCommand(t *testing.T) {
+	const (
+		original = "template: \"{{.key}}\"\n"
+		invalid  = "sanitize:\n  case: title\n"
+		valid    = "sanitize:\n  case: upper\n"
+	)
+
+	testCases := map[string]struct {
+		edits   []string
+		retries []bool
+		want    string
+		wantErr string
+	}{
+		"valid edit is saved": {
+			edits: []string{valid},
+			want:  valid,
+		},
+		"invalid edit is discarded": {
+			edits:   []string{invalid},
+			retries: []bool{false},
+			want:    original,
+			wantErr: "invalid value for sanitize.case",
+		},
+		"invalid edit is edited again": {
+			edits:   []string{invalid, valid},
+			retries: []bool{true},
+			want:    valid,
+		},
+		"retry then discard": {
+			edits:   []string{invalid, "sanitize:\n  max-words: many\n"},
+			retries: []bool{true, false},
+			want:    original,
+			wantErr: "the changes are discarded",
+		},
+		"unchanged file": {
+			edits: []string{original},
+			want:  original,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			file := initUserConfig(t, original)
+
+			cmd := config.NewEditCommand()
+			var edits *int
+			cmd.Edit, edits = editWith(t, tc.edits...)
+
+			var retries int
+			cmd.Retry = func(err error) (bool, error) {
+				require.Error(t, err)
+				require.Less(t, retries, len(tc.retries), "unexpected retry")
+				retry := tc.retries[retries]
+				retries++
+				return retry, nil
+			}
+
+			err := cmd.Execute(cmd.Command, nil)
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			saved, err := os.ReadFile(file)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, string(saved))
+			assert.Equal(t, len(tc.edits), *edits)
+			assert.Equal(t, len(tc.retries), retries)
+
+			// The copy that is edited is removed.
+			entries, err := os.ReadDir(filepath.Dir(file))
+			require.NoError(t, err)
+			assert.Len(t, entries, 1)
+		})
+	}
+}
+
+//nolint:paralleltest // Changes the environment and the global configuration.
+func TestEditCommandPromptFailure(t *testing.T) {
+	const original = "template: \"{{.key}}\"\n"
+	file := initUserConfig(t, original)
+
+	cmd := config.NewEditCommand()
+	cmd.Edit, _ = editWith(t, "sanitize:\n  case: title\n")
+	cmd.Retry = func(error) (bool, error) { return false, errors.New("no terminal") }
+
+	require.ErrorContains(t, cmd.Execute(cmd.Command, nil), "invalid value for sanitize.case")
+
+	saved, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, original, string(saved), "an invalid edit is never written")
+}
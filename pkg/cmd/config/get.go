@@ -11,9 +11,15 @@ import (
 	"github.com/spf13/cobra"
 )
 
+const (
+	ArgShowOrigin = "show-origin"
+)
+
 type GetCommand struct {
 	Command *cobra.Command
 	logger  *slog.Logger
+
+	ShowOrigin bool
 }
 
 func NewGetCommand() *GetCommand {
@@ -29,10 +35,14 @@ func NewGetCommand() *GetCommand {
 	cmd.Command = &cobra.Command{
 		Use:   "get <key>",
 		Short: "Get the value of a configuration option",
-		Args:  cobra.ExactArgs(1),
-		RunE:  cmd.Execute,
+		Long: "Get the value of a configuration option. Values are taken from the environment, the " +
+			cfg.RepoConfigFilename + " file of the repository and the user configuration, in that order.",
+		Args: cobra.ExactArgs(1),
+		RunE: cmd.Execute,
 	}
 
+	cmd.Command.Flags().BoolVar(&cmd.ShowOrigin, ArgShowOrigin, false, "Show where the value is set")
+
 	return cmd
 }
 
@@ -51,10 +61,24 @@ func (c *GetCommand) Execute(_ *cobra.Command, args []string) error {
 
 	val := opt.CurrentValue(*config)
 	if val == nil {
-		c.logger.Info("No value set")
+		if opt.Default == "" {
+			c.logger.Info("No value set")
+			return nil
+		}
+		val = &opt.Default
+	}
+
+	if !c.ShowOrigin {
+		c.logger.Info(fmt.Sprintf("%s=%s", key, *val))
 		return nil
 	}
 
-	c.logger.Info(fmt.Sprintf("%s=%s", key, *val))
+	origin, source := cfg.ValueOrigin(key)
+	if source != "" {
+		c.logger.Info(fmt.Sprintf("%s=%s", key, *val), "origin", origin, "source", source)
+	} else {
+		c.logger.Info(fmt.Sprintf("%s=%s", key, *val), "origin", origin)
+	}
+
 	return nil
 }
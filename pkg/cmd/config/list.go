@@ -0,0 +1,126 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"text/tabwriter"
+
+	cfg "github.com/MaikelVeen/branch/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+const (
+	ArgJSON = "json"
+)
+
+// ListCommand lists the configuration options.
+type ListCommand struct {
+	Command *cobra.Command
+
+	JSON bool
+}
+
+// OptionOutput is an option as listed by `branch config list`.
+type OptionOutput struct {
+	Key         string     `json:"key"`
+	Type        string     `json:"type"`
+	Description string     `json:"description"`
+	Value       *string    `json:"value"`
+	Default     string     `json:"default"`
+	Origin      cfg.Origin `json:"origin"`
+	Source      string     `json:"source,omitempty"`
+}
+
+func NewListCommand() *ListCommand {
+	cmd := &ListCommand{}
+	cmd.Command = &cobra.Command{
+		Use:   "list",
+		Short: "List the configuration options with their value, default and origin",
+		Args:  cobra.NoArgs,
+		RunE:  cmd.Execute,
+	}
+
+	cmd.Command.Flags().BoolVar(&cmd.JSON, ArgJSON, false, "Output as JSON")
+
+	return cmd
+}
+
+func (c *ListCommand) Execute(cmd *cobra.Command, _ []string) error {
+	config, err := cfg.Load()
+	if err != nil {
+		return err
+	}
+
+	options := ListOptions(config)
+
+	if c.JSON {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(options)
+	}
+
+	return WriteOptionTable(cmd.OutOrStdout(), options)
+}
+
+// ListOptions returns the registered options with their effective value in `config`,
+// sorted by key. The value of an option that is not set is its default.
+func ListOptions(config *cfg.Config) []OptionOutput {
+	keys := make([]string, 0, len(cfg.Options))
+	for key := range cfg.Options {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	options := make([]OptionOutput, 0, len(keys))
+	for _, key := range keys {
+		opt := cfg.Options[key]
+		origin, source := cfg.ValueOrigin(key)
+
+		value := opt.CurrentValue(*config)
+		if value == nil && opt.Default != "" {
+			value = &opt.Default
+			origin, source = cfg.OriginDefault, ""
+		}
+
+		options = append(options, OptionOutput{
+			Key:         key,
+			Type:        string(opt.Type),
+			Description: opt.Description,
+			Value:       value,
+			Default:     opt.Default,
+			Origin:      origin,
+			Source:      source,
+		})
+	}
+
+	return options
+}
+
+// WriteOptionTable writes `options` to `w` as an aligned table.
+func WriteOptionTable(w io.Writer, options []OptionOutput) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tVALUE\tDEFAULT\tORIGIN")
+
+	for _, o := range options {
+		value := "-"
+		if o.Value != nil {
+			value = *o.Value
+		}
+
+		defaultValue := o.Default
+		if defaultValue == "" {
+			defaultValue = "-"
+		}
+
+		origin := string(o.Origin)
+		if o.Source != "" {
+			origin += " " + o.Source
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", o.Key, value, defaultValue, origin)
+	}
+
+	return tw.Flush()
+}
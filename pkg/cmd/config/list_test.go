@@ -0,0 +1,80 @@
+package config_test
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/MaikelVeen/branch/pkg/cmd/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cfg "github.com/MaikelVeen/branch/pkg/config"
+)
+
+//nolint:paralleltest // Changes the environment and the global configuration.
+func TestListOptions(t *testing.T) {
+	file := initUserConfig(t, "template: \"{{.key}}\"\n")
+
+	loaded, err := cfg.Load()
+	require.NoError(t, err)
+
+	options := config.ListOptions(loaded)
+	require.Len(t, options, len(cfg.Options))
+
+	byKey := map[string]config.OptionOutput{}
+	for i, o := range options {
+		if i > 0 {
+			assert.Less(t, options[i-1].Key, o.Key, "options are sorted by key")
+		}
+		byKey[o.Key] = o
+	}
+
+	template := byKey[cfg.KeyTemplate]
+	require.NotNil(t, template.Value)
+	assert.Equal(t, "{{.key}}", *template.Value)
+	assert.Equal(t, cfg.DefaultTemplate, template.Default)
+	assert.Equal(t, cfg.OriginUser, template.Origin)
+	assert.Equal(t, file, template.Source)
+
+	// The default is the effective value of options that are not set.
+	sanitizeCase := byKey[cfg.KeySanitizeCase]
+	require.NotNil(t, sanitizeCase.Value)
+	assert.Equal(t, "lower", *sanitizeCase.Value)
+	assert.Equal(t, cfg.OriginDefault, sanitizeCase.Origin)
+
+	// Options without a default have no value.
+	assert.Nil(t, byKey[cfg.KeySanitizeFallback].Value)
+}
+
+func TestWriteOptionTable(t *testing.T) {
+	t.Parallel()
+
+	value := "{{.key}}"
+	options := []config.OptionOutput{
+		{Key: "template", Value: &value, Default: "{{.type}}", Origin: cfg.OriginUser, Source: "/home/me/config.yaml"},
+		{Key: "sanitize.fallback", Origin: cfg.OriginDefault},
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, config.WriteOptionTable(&b, options))
+
+	assert.Equal(t, "KEY                VALUE     DEFAULT    ORIGIN\n"+
+		"template           {{.key}}  {{.type}}  user /home/me/config.yaml\n"+
+		"sanitize.fallback  -         -          default\n", b.String())
+}
+
+//nolint:paralleltest // Changes the environment and the global configuration.
+func TestUnsetCommand(t *testing.T) {
+	file := initUserConfig(t, "template: \"{{.key}}\"\nsanitize:\n  case: upper\n")
+
+	cmd := config.NewUnsetCommand()
+	require.NoError(t, cmd.Execute(cmd.Command, []string{cfg.KeySanitizeCase}))
+
+	saved, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.NotContains(t, string(saved), "sanitize")
+	assert.Contains(t, string(saved), "template")
+
+	require.Error(t, cmd.Execute(cmd.Command, []string{"unknown"}))
+}
@@ -1,20 +1,34 @@
 package config
 
 import (
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
 	cfg "github.com/MaikelVeen/branch/pkg/config"
+	"github.com/lmittmann/tint"
 	"github.com/spf13/cobra"
 )
 
 // SetCommand is the command to update the configuration.
 type SetCommand struct {
 	Command *cobra.Command
+	logger  *slog.Logger
 }
 
 func NewSetCommand() *SetCommand {
-	cmd := &SetCommand{}
+	cmd := &SetCommand{
+		logger: slog.New(
+			tint.NewHandler(os.Stdout, &tint.Options{
+				Level:      slog.LevelInfo,
+				TimeFormat: time.Kitchen,
+			}),
+		),
+	}
 	cmd.Command = &cobra.Command{
 		Use:   "set <key> <value>",
-		Short: "Update the configuration",
+		Short: "Update the user configuration",
 		Args:  cobra.ExactArgs(2),
 		RunE:  cmd.Execute,
 	}
@@ -44,13 +58,21 @@ func (c *SetCommand) Execute(_ *cobra.Command, args []string) error {
 		return err
 	}
 
+	// The value is saved, but is not used when set at a higher precedence.
+	switch origin, source := cfg.ValueOrigin(opt.Key); origin {
+	case cfg.OriginEnv, cfg.OriginRepo:
+		c.logger.Warn(fmt.Sprintf("%s is overridden by %s", opt.Key, source))
+	case cfg.OriginUser, cfg.OriginDefault:
+	}
+
 	return nil
 }
 
 func ValididateKey(key string) (*cfg.Option, error) {
-	if _, ok := cfg.Options[key]; !ok {
+	opt, ok := cfg.LookupOption(key)
+	if !ok {
 		return nil, ErrInvalidKey
 	}
 
-	return cfg.Options[key], nil
+	return opt, nil
 }
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	cfg "github.com/MaikelVeen/branch/pkg/config"
+	"github.com/lmittmann/tint"
+	"github.com/spf13/cobra"
+)
+
+// UnsetCommand removes an option from the user configuration.
+type UnsetCommand struct {
+	Command *cobra.Command
+	logger  *slog.Logger
+}
+
+func NewUnsetCommand() *UnsetCommand {
+	cmd := &UnsetCommand{
+		logger: slog.New(
+			tint.NewHandler(os.Stdout, &tint.Options{
+				Level:      slog.LevelInfo,
+				TimeFormat: time.Kitchen,
+			}),
+		),
+	}
+
+	cmd.Command = &cobra.Command{
+		Use:   "unset <key>",
+		Short: "Remove an option from the user configuration, so its default is used",
+		Args:  cobra.ExactArgs(1),
+		RunE:  cmd.Execute,
+	}
+
+	return cmd
+}
+
+func (c *UnsetCommand) Execute(_ *cobra.Command, args []string) error {
+	opt, err := ValididateKey(args[0])
+	if err != nil {
+		return err
+	}
+
+	removed, err := cfg.Unset(opt.Key)
+	if err != nil {
+		return err
+	}
+
+	if !removed {
+		c.logger.Info(fmt.Sprintf("%s is not set in the user configuration", opt.Key))
+	}
+
+	switch origin, source := cfg.ValueOrigin(opt.Key); origin {
+	case cfg.OriginEnv, cfg.OriginRepo:
+		c.logger.Warn(fmt.Sprintf("%s is still set by %s", opt.Key, source))
+	case cfg.OriginUser, cfg.OriginDefault:
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -17,6 +18,8 @@ import (
 	"github.com/lmittmann/tint"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+
+	cfg "github.com/MaikelVeen/branch/pkg/config"
 )
 
 const (
@@ -24,6 +27,13 @@ const (
 	ArgBaseShort     = "b"
 	ArgTemplate      = "template"
 	ArgTemplateShort = "t"
+	ArgJQL           = "jql"
+	ArgRepair        = "repair"
+	ArgNoFetch       = "no-fetch"
+	ArgDirty         = "dirty"
+
+	// pickerLimit caps the number of issues offered by the issue picker.
+	pickerLimit = 100
 )
 
 type CreateCommand struct {
@@ -33,7 +43,11 @@ type CreateCommand struct {
 	git    *git.Commander
 
 	Template   string
-	BaseBranch string // TODO: Make configurable.
+	BaseBranch string
+	JQL        string
+	Repair     bool
+	NoFetch    bool
+	Dirty      string
 }
 
 func NewCreateCommand() *CreateCommand {
@@ -48,11 +62,14 @@ func NewCreateCommand() *CreateCommand {
 	}
 
 	cc.Command = &cobra.Command{
-		Use:     "create",
+		Use:     "create [issue-key]",
 		Aliases: []string{"c"},
-		Args:    cobra.ExactArgs(1),
+		Args:    cobra.RangeArgs(0, 1),
 		Short:   "Creates a new git branch based on a ticket identifier",
-		RunE:    cc.Execute,
+		Long: "Creates a new git branch based on a ticket identifier. Without an issue key, " +
+			"the issues matching the " + cfg.KeyPickerJQL + " query are shown to pick from. " +
+			"By default these are your unresolved issues in the open sprints.",
+		RunE: cc.Execute,
 	}
 
 	flagset := cc.Command.Flags()
@@ -61,7 +78,7 @@ func NewCreateCommand() *CreateCommand {
 		&cc.Template,
 		ArgTemplate,
 		ArgTemplateShort,
-		"{{.type}}/{{.key}}-{{.summary}}",
+		cfg.DefaultTemplate,
 		"Template to use for branch name",
 	)
 	_ = viper.BindPFlag(ArgTemplate, flagset.Lookup(ArgTemplate))
@@ -70,11 +87,39 @@ func NewCreateCommand() *CreateCommand {
 		&cc.BaseBranch,
 		ArgBase,
 		ArgBaseShort,
-		"main",
-		"Base branch to create the new branch from",
+		"",
+		"Base branch to create the new branch from, defaults to the default branch of the remote",
 	)
 	_ = viper.BindPFlag(ArgBase, flagset.Lookup(ArgBase))
 
+	flagset.StringVar(
+		&cc.JQL,
+		ArgJQL,
+		"",
+		"JQL query selecting the issues to pick from when no issue key is given",
+	)
+
+	flagset.BoolVar(
+		&cc.Repair,
+		ArgRepair,
+		false,
+		"Repair the branch name when git does not accept it instead of failing",
+	)
+
+	flagset.BoolVar(
+		&cc.NoFetch,
+		ArgNoFetch,
+		false,
+		"Do not fetch the base branch when creating branches from the remote, see "+cfg.KeyCreateFrom,
+	)
+
+	flagset.StringVar(
+		&cc.Dirty,
+		ArgDirty,
+		"",
+		"What to do with changes in the working tree: "+cfg.DirtyAbort+"|"+cfg.DirtyCarry+"|"+cfg.DirtyStash,
+	)
+
 	return cc
 }
 
@@ -85,46 +130,356 @@ func (c *CreateCommand) Execute(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	if err = c.checkPreconditions(); err != nil {
+	dirty, err := c.checkPreconditions()
+	if err != nil {
 		return err
 	}
 
-	if err = c.checkBaseBranch(c.BaseBranch); err != nil {
+	config, err := cfg.Load()
+	if err != nil {
 		return err
 	}
 
-	key := args[0]
-	issue, err := client.Issue.GetIssue(cmd.Context(), key)
+	mode := config.DirtyMode()
+	if err = cfg.ValidateDirtyMode(mode); err != nil {
+		return fmt.Errorf("invalid %s: %w", cfg.KeyCreateDirty, err)
+	}
+	if c.Dirty != "" {
+		if err = cfg.ValidateDirtyMode(c.Dirty); err != nil {
+			return fmt.Errorf("invalid --%s: %w", ArgDirty, err)
+		}
+		mode = strings.ToLower(c.Dirty)
+	}
+
+	if dirty && mode == cfg.DirtyAbort {
+		return fmt.Errorf("working tree is not clean, aborting. Commit the changes or use --%s %s or --%s %s",
+			ArgDirty, cfg.DirtyCarry, ArgDirty, cfg.DirtyStash)
+	}
+
+	sanitize, err := config.SanitizeOptions()
 	if err != nil {
-		c.logger.Error(fmt.Errorf("failed to get issue: %w", err).Error())
 		return err
 	}
 
-	branch, err := BranchNameFromTemplate(c.Template, issue)
+	var key string
+	if len(args) > 0 {
+		key = args[0]
+	} else {
+		jql := c.JQL
+		if jql == "" {
+			jql = config.PickerJQL()
+		}
+
+		if key, err = c.pickIssue(cmd.Context(), client, jql); err != nil {
+			return err
+		}
+	}
+
+	// The names are needed to look up custom fields, such as the sprint, by name.
+	issue, err := client.Issue.GetIssue(cmd.Context(), key, "names")
+	if err != nil {
+		return describeIssueError(key, err)
+	}
+
+	branch, err := BranchNameFromTemplate(c.Template, issue, WithSanitizeOptions(sanitize), WithPrefixes(config.Prefix))
 	if err != nil {
 		return err
 	}
 
-	if err = c.checkoutOrCreateBranch(branch); err != nil {
+	// Validate the name before switching branches, the template can
+	// produce names that git does not accept.
+	if c.Repair || config.RepairBranchNames() {
+		repaired, err := git.RepairRefName(branch)
+		if err != nil {
+			return err
+		}
+		if repaired != branch {
+			c.logger.Warn(fmt.Sprintf("repaired invalid branch name %s", branch))
+		}
+		branch = repaired
+	} else if err = git.ValidateRefName(branch); err != nil {
+		return fmt.Errorf("%w, change the template or use --%s", err, ArgRepair)
+	}
+
+	base, err := c.resolveBaseBranch(config, issue, flagPassed(cmd, ArgBase))
+	if err != nil {
+		return err
+	}
+
+	var stash string
+	if dirty && mode == cfg.DirtyStash {
+		if stash, err = c.git.StashPush(exec.Command, "branch: changes before creating "+branch); err != nil {
+			return fmt.Errorf("failed to stash the changes in the working tree: %w", err)
+		}
+	}
+
+	err = c.switchToBranch(branch, base, config.CreateFromRemote(), dirty && mode == cfg.DirtyCarry)
+
+	// The changes are applied to the original branch when switching failed.
+	if stash != "" {
+		if popErr := c.popStash(stash); popErr != nil {
+			if err != nil {
+				return fmt.Errorf("%w. %w", err, popErr)
+			}
+			c.logger.Warn(popErr.Error())
+		}
+	}
+
+	if err != nil {
 		return err
 	}
 
 	c.logger.Info(fmt.Sprintf("checked out %s", branch))
+
+	c.runPostCreateActions(cmd.Context(), client, issue, branch, config.CreateFor(projectKey(issue)))
 	return nil
 }
 
-func (c *CreateCommand) checkPreconditions() error {
+// pickIssue lets the user choose one of the issues matching `jql` and returns its key.
+func (c *CreateCommand) pickIssue(ctx context.Context, client *jira.Client, jql string) (string, error) {
+	opts := &jira.SearchOptions{
+		Fields:     []string{"summary", "status"},
+		MaxResults: pickerLimit,
+	}
+
+	var issues []jira.Issue
+	it := client.Search.All(jql, opts)
+	for len(issues) < pickerLimit && it.Next(ctx) {
+		issues = append(issues, *it.Issue())
+	}
+	if err := it.Err(); err != nil {
+		return "", fmt.Errorf("failed to search issues with %q: %w", jql, err)
+	}
+
+	if len(issues) == 0 {
+		return "", fmt.Errorf("no issues match %q, pass an issue key or change %s", jql, cfg.KeyPickerJQL)
+	}
+
+	options := make([]huh.Option[string], 0, len(issues))
+	for _, issue := range issues {
+		options = append(options, huh.NewOption(IssueLabel(&issue), issue.Key))
+	}
+
+	var key string
+	if err := huh.NewSelect[string]().
+		Title("Select an issue").
+		Description("Press / to filter").
+		Options(options...).
+		Height(min(len(options), 10) + 2).
+		Value(&key).
+		Run(); err != nil {
+		return "", err
+	}
+
+	return key, nil
+}
+
+// IssueLabel returns the label of `issue` in the issue picker.
+func IssueLabel(issue *jira.Issue) string {
+	label := fmt.Sprintf("%s  %s", issue.Key, issue.Fields.Summary)
+	if issue.Fields.Status.Name != "" {
+		label += fmt.Sprintf(" [%s]", issue.Fields.Status.Name)
+	}
+
+	return label
+}
+
+// runPostCreateActions performs the configured actions on `issue` after `branch` was
+// checked out. Failures are logged, the branch has been created at this point.
+func (c *CreateCommand) runPostCreateActions(
+	ctx context.Context,
+	client *jira.Client,
+	issue *jira.Issue,
+	branch string,
+	actions cfg.CreateConfig,
+) {
+	if actions.Assign != nil && *actions.Assign {
+		if err := c.assignToSelf(ctx, client, issue); err != nil {
+			c.logger.Warn(fmt.Sprintf("failed to assign %s: %s", issue.Key, err))
+		} else {
+			c.logger.Info(fmt.Sprintf("assigned %s to you", issue.Key))
+		}
+	}
+
+	if actions.Transition != nil && *actions.Transition != "" {
+		if err := transitionIssue(ctx, client, issue, *actions.Transition); err != nil {
+			c.logger.Warn(fmt.Sprintf("failed to move %s to %s: %s", issue.Key, *actions.Transition, err))
+		} else {
+			c.logger.Info(fmt.Sprintf("moved %s to %s", issue.Key, *actions.Transition))
+		}
+	}
+
+	if actions.Comment != nil && *actions.Comment {
+		text := fmt.Sprintf("Created branch %s", branch)
+		if _, err := client.Issue.AddComment(ctx, issue.Key, text); err != nil {
+			c.logger.Warn(fmt.Sprintf("failed to comment on %s: %s", issue.Key, err))
+		} else {
+			c.logger.Info(fmt.Sprintf("commented branch name on %s", issue.Key))
+		}
+	}
+}
+
+// assignToSelf assigns `issue` to the authenticated user.
+func (c *CreateCommand) assignToSelf(ctx context.Context, client *jira.Client, issue *jira.Issue) error {
+	user := &jira.User{}
+	if authCtx, ok := auth.FromContext(ctx); ok {
+		user.AccountID = authCtx.AccountID
+	}
+
+	// Contexts created before the account id was stored, and Jira Server
+	// which identifies users by name, need to look the user up.
+	if user.AccountID == "" || client.Deployment() == jira.DeploymentServer {
+		var err error
+		if user, err = client.Myself.Myself(ctx); err != nil {
+			return err
+		}
+	}
+
+	return client.Issue.AssignIssue(ctx, issue.Key, user)
+}
+
+// transitionIssue moves `issue` to the transition or status called `name`,
+// unless the issue already has that status.
+func transitionIssue(ctx context.Context, client *jira.Client, issue *jira.Issue, name string) error {
+	if strings.EqualFold(issue.Fields.Status.Name, name) {
+		return nil
+	}
+
+	transitions, err := client.Issue.GetTransitions(ctx, issue.Key)
+	if err != nil {
+		return err
+	}
+
+	transition, err := jira.FindTransition(transitions, name)
+	if err != nil {
+		return err
+	}
+
+	if err = transition.Validate(); err != nil {
+		return err
+	}
+
+	return client.Issue.DoTransition(ctx, issue.Key, transition.ID, nil)
+}
+
+// projectKey returns the key of the project `issue` belongs to.
+func projectKey(issue *jira.Issue) string {
+	if issue.Fields.Project.Key != "" {
+		return issue.Fields.Project.Key
+	}
+
+	key, _, _ := strings.Cut(issue.Key, "-")
+	return key
+}
+
+// checkPreconditions checks that the working directory is a git repository and
+// reports whether the working tree has changes.
+func (c *CreateCommand) checkPreconditions() (bool, error) {
 	if _, err := c.git.Status(exec.Command); err != nil {
-		return errors.New("checking git status failed, are you in a git repo?")
+		return false, errors.New("checking git status failed, are you in a git repo?")
+	}
+
+	// DiffIndex returns an error when there is a diff.
+	dirty := c.git.DiffIndex(exec.Command, "HEAD") != nil
+	return dirty, nil
+}
+
+// switchToBranch creates `b` from `base` and checks it out. With `fromRemote` it is
+// created from the base branch on the remote, otherwise from the local base branch.
+// With `carry` the changes in the working tree are carried over, which is only done
+// when the branch starts at HEAD.
+func (c *CreateCommand) switchToBranch(b, base string, fromRemote, carry bool) error {
+	if fromRemote {
+		return c.createFromRemote(b, base, carry)
+	}
+
+	if carry {
+		start := base
+		if err := c.git.ShowRef(exec.Command, b); err == nil {
+			start = b
+		}
+
+		if err := c.checkCarry(start); err != nil {
+			return err
+		}
 	}
 
-	if err := c.git.DiffIndex(exec.Command, "HEAD"); err != nil {
-		return errors.New("working tree is not clean, aborting")
+	if err := c.checkBaseBranch(base); err != nil {
+		return err
+	}
+
+	return c.checkoutOrCreateBranch(b)
+}
+
+// checkCarry returns an error when HEAD is not at `start`, where the new branch starts.
+// The changes in the working tree are only carried over when they were made on the
+// commit the branch starts at, otherwise they may conflict or end up on the wrong code.
+func (c *CreateCommand) checkCarry(start string) error {
+	head, err := c.git.RevParse(exec.Command, "HEAD")
+	if err != nil {
+		return err
+	}
+
+	if commit, err := c.git.RevParse(exec.Command, start); err != nil || commit != head {
+		return fmt.Errorf("can not carry the changes in the working tree over, HEAD is not at %s. "+
+			"Commit the changes or use --%s %s", start, ArgDirty, cfg.DirtyStash)
 	}
 
 	return nil
 }
 
+// popStash applies the latest stash, which is `stash`, and drops it. When that fails
+// the stash is kept, and the returned error names it and explains how to continue.
+func (c *CreateCommand) popStash(stash string) error {
+	if err := c.git.StashPop(exec.Command); err == nil {
+		return nil
+	}
+
+	if files, err := c.git.UnmergedFiles(exec.Command); err == nil && len(files) > 0 {
+		return fmt.Errorf("the stashed changes conflict in %s. Resolve the conflicts, then run "+
+			"`git stash drop` to remove the stash (stash@{0}, %s)", strings.Join(files, ", "), stash)
+	}
+
+	return fmt.Errorf("the stashed changes could not be applied and are kept in stash@{0} (%s). "+
+		"Apply them with `git stash pop`", stash)
+}
+
+// resolveBaseBranch returns the branch to create the branch of `issue` from: the
+// --base flag, the base rule of the issue, the configured base branch or the
+// default branch of the remote, in that order. Base rules with a glob pattern
+// resolve to the matching branch with the highest version.
+func (c *CreateCommand) resolveBaseBranch(config *cfg.Config, issue *jira.Issue, flagPassed bool) (string, error) {
+	if flagPassed && c.BaseBranch != "" {
+		return c.BaseBranch, nil
+	}
+
+	issueType := issue.Fields.Issuetype.Name
+	if base, ok := config.BaseFor(issueType, config.Prefix.For(issueType, issue.Fields.Labels)); ok {
+		if !git.IsBranchPattern(base) {
+			return base, nil
+		}
+
+		branch, err := c.git.LatestBranch(exec.Command, base, git.DefaultRemote)
+		if err != nil {
+			return "", fmt.Errorf("failed to find the base branch of %s: %w", issue.Key, err)
+		}
+
+		return branch, nil
+	}
+
+	// The flag holds the configured base branch when it is not passed.
+	if c.BaseBranch != "" {
+		return c.BaseBranch, nil
+	}
+
+	base, err := defaultBaseBranch(c.git)
+	if err != nil {
+		c.logger.Warn(fmt.Sprintf("%s, using %s", err, base))
+	}
+
+	return base, nil
+}
+
 // checkBaseBranch checks if the configured base branch is currently
 // set and ask if the user wants to switch if that is not the case.
 func (c *CreateCommand) checkBaseBranch(base string) error {
@@ -183,23 +538,130 @@ func (c *CreateCommand) checkoutOrCreateBranch(b string) error {
 	return nil
 }
 
+// createFromRemote creates `b` from the `base` branch on the remote and checks it
+// out, with the remote base branch as upstream. The base branch is fetched first,
+// unless --no-fetch is passed. The local base branch is never checked out, and an
+// existing branch `b` is checked out as it is. With `carry`, see checkCarry.
+func (c *CreateCommand) createFromRemote(b, base string, carry bool) error {
+	if current, err := c.git.ShortSymbolicRef(exec.Command); err == nil && current == b {
+		return nil
+	}
+
+	if err := c.git.ShowRef(exec.Command, b); err == nil {
+		if carry {
+			if err = c.checkCarry(b); err != nil {
+				return err
+			}
+		}
+
+		return c.git.Checkout(exec.Command, b)
+	}
+
+	upstream := git.DefaultRemote + "/" + base
+	if !c.NoFetch {
+		if err := c.git.Fetch(exec.Command, git.DefaultRemote, base); err != nil {
+			return fmt.Errorf("failed to fetch %s, use --%s to create the branch from %s as it is: %w",
+				upstream, ArgNoFetch, upstream, err)
+		}
+	}
+
+	if err := c.git.ShowRemoteRef(exec.Command, git.DefaultRemote, base); err != nil {
+		return fmt.Errorf("%s does not exist, push %s or set %s to %s", upstream, base, cfg.KeyCreateFrom, cfg.CreateFromLocal)
+	}
+
+	if carry {
+		if err := c.checkCarry(upstream); err != nil {
+			return err
+		}
+	}
+
+	if err := c.git.TrackingBranch(exec.Command, b, upstream); err != nil {
+		return fmt.Errorf("failed to create %s from %s: %w", b, upstream, err)
+	}
+
+	if err := c.git.Checkout(exec.Command, b); err != nil {
+		return fmt.Errorf("could not checkout the %s branch", b)
+	}
+
+	c.logger.Info(fmt.Sprintf("created %s from %s", b, upstream))
+	return nil
+}
+
+// describeIssueError turns an error returned while fetching `key` into
+// a message that tells the user what went wrong.
+func describeIssueError(key string, err error) error {
+	switch {
+	case jira.IsNotFound(err):
+		return fmt.Errorf("issue %s does not exist or you do not have permission to see it", key)
+	case jira.IsUnauthorized(err):
+		return errors.New("jira rejected the credentials, run `branch jira auth init` to authenticate again")
+	case jira.IsForbidden(err):
+		return fmt.Errorf("you do not have permission to view issue %s", key)
+	case jira.IsRateLimited(err):
+		var apiErr *jira.ErrorResponse
+		if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+			return fmt.Errorf("jira rate limit exceeded, try again in %s", apiErr.RetryAfter)
+		}
+		return errors.New("jira rate limit exceeded, try again later")
+	default:
+		return fmt.Errorf("failed to get issue %s: %w", key, err)
+	}
+}
+
+// BranchNameOption configures BranchNameFromTemplate.
+type BranchNameOption func(*branchNameOptions)
+
+type branchNameOptions struct {
+	sanitize git.SanitizeOptions
+	prefixes cfg.PrefixConfig
+}
+
+// WithSanitizeOptions returns an option to set how the summary is sanitized,
+// which defaults to git.DefaultSanitizeOptions.
+func WithSanitizeOptions(opts git.SanitizeOptions) BranchNameOption {
+	return func(o *branchNameOptions) {
+		o.sanitize = opts
+	}
+}
+
+// WithPrefixes returns an option to set the branch prefixes of issue types and
+// labels, which default to cfg.DefaultTypePrefixes.
+func WithPrefixes(prefixes cfg.PrefixConfig) BranchNameOption {
+	return func(o *branchNameOptions) {
+		o.prefixes = prefixes
+	}
+}
+
 // BranchNameFromTemplate generates a branch name from a given template and Jira issue.
-func BranchNameFromTemplate(tmpl string, issue *jira.Issue) (string, error) {
-	t, err := template.New("branchName").Parse(tmpl)
+// The template is executed with the TemplateData of the issue and TemplateFuncs.
+func BranchNameFromTemplate(tmpl string, issue *jira.Issue, opts ...BranchNameOption) (string, error) {
+	o := branchNameOptions{sanitize: git.DefaultSanitizeOptions()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	t, err := template.New("branchName").
+		Funcs(TemplateFuncs(o.sanitize, o.prefixes)).
+		Option("missingkey=error").
+		Parse(tmpl)
 	if err != nil {
 		return "", err
 	}
 
-	params := map[string]string{
-		"key":     issue.Key,
-		"type":    strings.ToLower(issue.Fields.Issuetype.Name),
-		"summary": git.FormatAsValidRef(issue.Fields.Summary),
+	summary, err := git.Sanitize(issue.Fields.Summary, o.sanitize)
+	if err != nil {
+		return "", err
 	}
 
+	data := NewTemplateData(issue, summary, o.prefixes)
+	addMissingCustomFields(t, data)
+
 	var b strings.Builder
-	if err = t.Execute(&b, params); err != nil {
+	if err = t.Execute(&b, data); err != nil {
 		return "", err
 	}
 
-	return b.String(), nil
+	// Empty values, such as a summary in a script that can not be transliterated
+	// or an issue without a sprint, leave dangling separators.
+	return git.TrimDanglingSeparators(b.String()), nil
 }
@@ -4,8 +4,13 @@ import (
 	"testing"
 
 	"github.com/MaikelVeen/branch/pkg/cmd"
+	"github.com/MaikelVeen/branch/pkg/git"
 	"github.com/MaikelVeen/branch/pkg/jira"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	cfg "github.com/MaikelVeen/branch/pkg/config"
 )
 
 func TestBranchNameFromTemplate(t *testing.T) {
@@ -56,6 +61,32 @@ func TestBranchNameFromTemplate(t *testing.T) {
 			want:    "bug/TEST-123-this-is-a-test-issue-with-special-characters",
 			wantErr: false,
 		},
+		{
+			name:     "issue with a non-English summary",
+			template: "{{.type}}/{{.key}}-{{.summary}}",
+			issue: &jira.Issue{
+				Key: "TEST-123",
+				Fields: jira.IssueFields{
+					Issuetype: jira.IssueType{Name: "Bug"},
+					Summary:   "Überprüfung der Zahlungsmethode",
+				},
+			},
+			want:    "bug/TEST-123-uberprufung-der-zahlungsmethode",
+			wantErr: false,
+		},
+		{
+			name:     "issue with a summary that can not be transliterated",
+			template: "{{.type}}/{{.key}}-{{.summary}}",
+			issue: &jira.Issue{
+				Key: "TEST-123",
+				Fields: jira.IssueFields{
+					Issuetype: jira.IssueType{Name: "Bug"},
+					Summary:   "修复登录",
+				},
+			},
+			want:    "bug/TEST-123",
+			wantErr: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -72,3 +103,101 @@ func TestBranchNameFromTemplate(t *testing.T) {
 		})
 	}
 }
+
+func TestBranchNameFromTemplateWithSanitizeOptions(t *testing.T) {
+	t.Parallel()
+
+	issue := &jira.Issue{
+		Key: "TEST-1",
+		Fields: jira.IssueFields{
+			Issuetype: jira.IssueType{Name: "Story"},
+			Summary:   "Add the export to the reports page",
+		},
+	}
+
+	opts := git.DefaultSanitizeOptions()
+	opts.Separator = "_"
+	opts.StopWords = []string{"the", "to"}
+
+	got, err := cmd.BranchNameFromTemplate("{{.key}}/{{.summary}}", issue, cmd.WithSanitizeOptions(opts))
+	require.NoError(t, err)
+	require.Equal(t, "TEST-1/add_export_reports_page", got)
+}
+
+func TestIssueLabel(t *testing.T) {
+	t.Parallel()
+
+	issue := &jira.Issue{Key: "TEST-1", Fields: jira.IssueFields{Summary: "Fix the login"}}
+	require.Equal(t, "TEST-1  Fix the login", cmd.IssueLabel(issue))
+
+	issue.Fields.Status.Name = "In Progress"
+	require.Equal(t, "TEST-1  Fix the login [In Progress]", cmd.IssueLabel(issue))
+}
+
+func TestFlagPassed(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		args       []string
+		wantBase   string
+		wantPassed bool
+	}{
+		"set from the configuration": {
+			args:     nil,
+			wantBase: "develop",
+		},
+		"passed with the configured value": {
+			args:       []string{"--" + cmd.ArgBase, "develop"},
+			wantBase:   "develop",
+			wantPassed: true,
+		},
+		"passed with another value": {
+			args:       []string{"--" + cmd.ArgBase, "main"},
+			wantBase:   "main",
+			wantPassed: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cc := cmd.NewCreateCommand()
+			require.NoError(t, cc.Command.Flags().Parse(tc.args))
+
+			v := viper.New()
+			v.Set(cfg.KeyBase, "develop")
+			cmd.BindFlags(cc.Command, v)
+
+			assert.Equal(t, tc.wantBase, cc.BaseBranch)
+			assert.Equal(t, tc.wantPassed, cmd.FlagPassed(cc.Command, cmd.ArgBase))
+		})
+	}
+}
+
+func TestResolveBaseBranch(t *testing.T) {
+	t.Parallel()
+
+	develop := "develop"
+	config := &cfg.Config{
+		Base:      &develop,
+		BaseRules: map[string]string{"bug": "hotfix-base"},
+	}
+	bug := &jira.Issue{Key: "ABC-1", Fields: jira.IssueFields{Issuetype: jira.IssueType{Name: "Bug"}}}
+	story := &jira.Issue{Key: "ABC-2", Fields: jira.IssueFields{Issuetype: jira.IssueType{Name: "Story"}}}
+
+	cc := cmd.NewCreateCommand()
+	cc.BaseBranch = develop
+
+	base, err := cc.ResolveBaseBranch(config, bug, true)
+	require.NoError(t, err)
+	assert.Equal(t, "develop", base, "a passed flag takes precedence over the rules, also when it equals the configuration")
+
+	base, err = cc.ResolveBaseBranch(config, bug, false)
+	require.NoError(t, err)
+	assert.Equal(t, "hotfix-base", base, "rules take precedence over the configuration")
+
+	base, err = cc.ResolveBaseBranch(config, story, false)
+	require.NoError(t, err)
+	assert.Equal(t, "develop", base)
+}
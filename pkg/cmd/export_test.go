@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"github.com/MaikelVeen/branch/pkg/jira"
+
+	cfg "github.com/MaikelVeen/branch/pkg/config"
+)
+
+// Exported for tests in the cmd_test package.
+var (
+	BindFlags  = bindFlags
+	FlagPassed = flagPassed
+)
+
+func (c *CreateCommand) ResolveBaseBranch(config *cfg.Config, issue *jira.Issue, flagPassed bool) (string, error) {
+	return c.resolveBaseBranch(config, issue, flagPassed)
+}
@@ -5,9 +5,18 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/lmittmann/tint"
 	"github.com/spf13/cobra"
-	"github.com/zalando/go-keyring"
+
+	"github.com/MaikelVeen/branch/pkg/credentials"
+	"github.com/MaikelVeen/branch/pkg/oauth"
 
 	client "github.com/MaikelVeen/branch/pkg/jira"
 )
@@ -27,7 +36,12 @@ func NewCommand() *Command {
 	}
 
 	cmd.Command.AddCommand(NewInitCommand().Command)
+	cmd.Command.AddCommand(NewLoginCommand().Command)
 	cmd.Command.AddCommand(NewShowCommand().Command)
+	cmd.Command.AddCommand(NewListCommand().Command)
+	cmd.Command.AddCommand(NewUseCommand().Command)
+	cmd.Command.AddCommand(NewRemoveCommand().Command)
+	cmd.Command.AddCommand(NewLogoutCommand().Command)
 	return cmd
 }
 
@@ -44,37 +58,104 @@ const (
 // Context encapsulates the details needed to authenticate with Jira
 // and the user details, fetched after authentication.
 type Context struct {
+	// Profile is the name of the profile the context is stored under.
+	Profile string `json:"-"`
+
+	// Deployment is either "cloud" or "server", empty for contexts saved
+	// before Jira Server was supported.
+	Deployment string `json:"deployment,omitempty"`
+	// BaseURL is the full URL of the Jira instance. Contexts saved before
+	// it was introduced only have a Subdomain.
+	BaseURL string `json:"baseUrl,omitempty"`
+
 	EmailAddress string `json:"emailAddress"`
 	Subdomain    string `json:"subdomain"`
 	Token        string `json:"token"`
 	DisplayName  string `json:"displayName"`
+	AccountID    string `json:"accountId"`
+
+	// OAuth is set for contexts created with jira auth login, which
+	// authenticate with OAuth 2.0 instead of an API token.
+	OAuth *OAuthContext `json:"oauth,omitempty"`
+}
+
+// OAuthContext holds the OAuth 2.0 app and tokens of a context.
+type OAuthContext struct {
+	ClientID     string `json:"clientId"`
+	ClientSecret string `json:"clientSecret"`
+	// TokenURL is the endpoint used to refresh the token, empty for the Atlassian endpoint.
+	TokenURL string `json:"tokenUrl,omitempty"`
+	// CloudID identifies the site, APIURL is the base URL of its API.
+	CloudID string       `json:"cloudId"`
+	APIURL  string       `json:"apiUrl"`
+	Token   *oauth.Token `json:"token"`
+}
+
+// config returns the OAuth configuration used to refresh the token.
+func (o *OAuthContext) config() *oauth.Config {
+	config := oauth.NewConfig(o.ClientID, o.ClientSecret)
+	if o.TokenURL != "" {
+		config.TokenURL = o.TokenURL
+	}
+
+	return config
+}
+
+// AuthMethod returns how the context authenticates, "oauth" or "token".
+func (c *Context) AuthMethod() string {
+	if c.OAuth != nil {
+		return "oauth"
+	}
+
+	return "token"
+}
+
+// URL returns the base URL of the Jira instance.
+func (c *Context) URL() string {
+	if c.BaseURL != "" {
+		return strings.TrimSuffix(c.BaseURL, "/")
+	}
+
+	return fmt.Sprintf(client.BaseURLTemplate, c.Subdomain)
 }
 
-// Save saves the user context to the keyring.
+// Save saves the user context to the credential store under its profile.
 func (c *Context) Save() error {
+	if c.Profile == "" {
+		c.Profile = DefaultProfile
+	}
+
+	if err := ValidateProfileName(c.Profile); err != nil {
+		return err
+	}
+
 	jsonData, err := json.Marshal(c)
 	if err != nil {
 		return err
 	}
 
-	if err = keyring.Set(keyringService, keyringUser, string(jsonData)); err != nil {
-		return fmt.Errorf("failed to set keyring: %w", err)
+	if err = store.Set(profileKeyringUserFor(c.Profile), string(jsonData)); err != nil {
+		return err
 	}
 
-	return nil
+	return addProfile(c.Profile)
 }
 
-// Load loads the user context from the keyring.
-func LoadUserContext() (*Context, error) {
-	jsonData, err := keyring.Get(keyringService, keyringUser)
+// LoadUserContext loads the user context of `profile` from the credential store.
+func LoadUserContext(profile string) (*Context, error) {
+	if profile == "" {
+		profile = DefaultProfile
+	}
+
+	jsonData, err := store.Get(profileKeyringUserFor(profile))
 	if err != nil {
-		if errors.Is(err, keyring.ErrNotFound) {
+		if errors.Is(err, credentials.ErrNotFound) {
 			return nil, ErrAuthContextMissing
 		}
-		return nil, fmt.Errorf("failed to get keyring: %w", err)
+		return nil, err
 	}
 
-	var c Context
+	c := Context{Profile: profile}
 	if err = json.Unmarshal([]byte(jsonData), &c); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal keyring data: %w", err)
 	}
@@ -83,21 +164,127 @@ func LoadUserContext() (*Context, error) {
 }
 
 // newClient creates a new Jira client with the given authentication context.
+// Jira Cloud uses basic authentication with an API token or OAuth 2.0,
+// Jira Server and Data Center use a personal access token.
 func newClient(authCtx *Context) (*client.Client, error) {
-	baseURL := fmt.Sprintf(client.BaseURLTemplate, authCtx.Subdomain)
-	c, err := client.NewClient(baseURL, client.WithBasicAuthentication(authCtx.EmailAddress, authCtx.Token))
+	if authCtx.OAuth != nil {
+		return newOAuthClient(authCtx)
+	}
+
+	deployment, err := client.ParseDeployment(authCtx.Deployment)
 	if err != nil {
 		return nil, err
 	}
 
-	return c, nil
+	opts := []func(*client.Client) error{
+		client.WithDeployment(deployment),
+		client.WithRetry(client.DefaultRetryPolicy()),
+	}
+
+	if deployment == client.DeploymentServer {
+		opts = append(opts, client.WithBearerAuthentication(authCtx.Token))
+	} else {
+		opts = append(opts, client.WithBasicAuthentication(authCtx.EmailAddress, authCtx.Token))
+	}
+
+	return client.NewClient(authCtx.URL(), opts...)
+}
+
+// newOAuthClient creates a Jira client that refreshes the access token when it
+// expires and saves the refreshed token, as refresh tokens are rotated. A token
+// that can not be saved is logged and used for the rest of the command.
+func newOAuthClient(authCtx *Context) (*client.Client, error) {
+	transport := oauth.NewTransport(authCtx.OAuth.config(), authCtx.OAuth.Token, func(token *oauth.Token) error {
+		authCtx.OAuth.Token = token
+		if err := authCtx.Save(); err != nil {
+			return fmt.Errorf("failed to save the refreshed token: %w", err)
+		}
+		return nil
+	})
+	transport.Logger = slog.New(tint.NewHandler(os.Stdout, &tint.Options{
+		Level:      slog.LevelInfo,
+		TimeFormat: time.Kitchen,
+	}))
+
+	return client.NewClient(
+		authCtx.OAuth.APIURL,
+		client.WithDeployment(client.DeploymentCloud),
+		client.WithRetry(client.DefaultRetryPolicy()),
+		client.WithHTTPClient(&http.Client{Timeout: client.DefaultTimeout, Transport: transport}),
+	)
+}
+
+// session resolves the profile and loads its auth context when they are first
+// needed, so commands that do not use Jira never read the credential store,
+// which may ask for a passphrase.
+type session struct {
+	flag string
+
+	profileOnce sync.Once
+	profile     string
+	profileErr  error
+
+	contextOnce sync.Once
+	authCtx     *Context
+	contextErr  error
+}
+
+// WithSession returns a copy of `ctx` from which the profile selected with
+// `profileFlag` and its auth context are loaded when first used.
+func WithSession(ctx context.Context, profileFlag string) context.Context {
+	return context.WithValue(ctx, DefaultContextKey, &session{flag: profileFlag})
+}
+
+func (s *session) resolveProfile() (string, error) {
+	s.profileOnce.Do(func() {
+		s.profile, s.profileErr = ResolveProfile(s.flag)
+	})
+
+	return s.profile, s.profileErr
+}
+
+func (s *session) loadContext() (*Context, error) {
+	s.contextOnce.Do(func() {
+		profile, err := s.resolveProfile()
+		if err != nil {
+			s.contextErr = err
+			return
+		}
+
+		s.authCtx, s.contextErr = LoadUserContext(profile)
+	})
+
+	return s.authCtx, s.contextErr
+}
+
+// loadFromContext returns the auth context stored in `ctx`, loading it when
+// `ctx` holds a session. Returns ErrAuthContextMissing when there is none.
+func loadFromContext(ctx context.Context) (*Context, error) {
+	switch v := ctx.Value(DefaultContextKey).(type) {
+	case *Context:
+		return v, nil
+	case *session:
+		return v.loadContext()
+	default:
+		return nil, ErrAuthContextMissing
+	}
+}
+
+// FromContext returns the auth context stored in `ctx`, if any.
+func FromContext(ctx context.Context) (*Context, bool) {
+	authCtx, err := loadFromContext(ctx)
+	return authCtx, err == nil
 }
 
 // NewClientFromContext creates a new Jira client from the given context.
 func NewClientFromContext(ctx context.Context) (*client.Client, error) {
-	if authCtx, ok := ctx.Value(DefaultContextKey).(*Context); ok {
-		return newClient(authCtx)
+	authCtx, err := loadFromContext(ctx)
+	if err != nil {
+		if errors.Is(err, ErrAuthContextMissing) {
+			return nil, errors.New("no Jira authentication context found, create one with jira auth init")
+		}
+		return nil, err
 	}
 
-	return nil, errors.New("no Jira authentication context found, create one with jira auth init")
+	return newClient(authCtx)
 }
@@ -0,0 +1,27 @@
+package auth
+
+const (
+	// ExitInvalidCredentials is the exit code used when Jira rejects the credentials.
+	ExitInvalidCredentials = 2
+	// ExitUnreachable is the exit code used when the Jira instance can not be reached.
+	ExitUnreachable = 3
+)
+
+// ExitError is an error that should terminate the program with a specific exit code.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the exit code the program should terminate with.
+func (e *ExitError) ExitCode() int {
+	return e.Code
+}
@@ -1,20 +1,44 @@
 package auth
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"net/url"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/huh"
 	"github.com/lmittmann/tint"
 	"github.com/spf13/cobra"
+
+	client "github.com/MaikelVeen/branch/pkg/jira"
+	"github.com/MaikelVeen/branch/pkg/oauth"
+)
+
+const (
+	ArgEmail      = "email"
+	ArgSite       = "site"
+	ArgDeployment = "deployment"
+	ArgTokenStdin = "token-stdin"
+
+	EnvEmail      = "BRANCH_JIRA_EMAIL"
+	EnvSite       = "BRANCH_JIRA_SITE"
+	EnvDeployment = "BRANCH_JIRA_DEPLOYMENT"
+	EnvToken      = "BRANCH_JIRA_TOKEN"
 )
 
 type InitCommand struct {
 	Command *cobra.Command
 
 	logger *slog.Logger
+
+	Email      string
+	Site       string
+	Deployment string
+	TokenStdin bool
 }
 
 func NewInitCommand() *InitCommand {
@@ -30,56 +54,258 @@ func NewInitCommand() *InitCommand {
 	cmd.Command = &cobra.Command{
 		Use:   "init",
 		Short: "Initialize the Jira authentication",
-		RunE:  cmd.Execute,
-		Args:  cobra.NoArgs,
+		Long: "Initialize the Jira authentication. Without flags an interactive form is shown. " +
+			"The form is skipped when the site and token are provided with flags or the " +
+			EnvSite + ", " + EnvEmail + ", " + EnvDeployment + " and " + EnvToken + " environment variables.\n\n" +
+			"Exits with code 2 when the credentials are rejected and code 3 when Jira can not be reached.",
+		Example: "  echo $TOKEN | branch jira auth init --site acme --email me@acme.com --token-stdin",
+		RunE:    cmd.Execute,
+		Args:    cobra.NoArgs,
 	}
 
+	flagset := cmd.Command.Flags()
+	flagset.StringVar(&cmd.Email, ArgEmail, "", "Email address of the Jira Cloud account")
+	flagset.StringVar(&cmd.Site, ArgSite, "", "Jira Cloud subdomain or full URL of the Jira instance")
+	flagset.StringVar(&cmd.Deployment, ArgDeployment, "", "Deployment type, cloud or server (default cloud)")
+	flagset.BoolVar(&cmd.TokenStdin, ArgTokenStdin, false, "Read the API or personal access token from stdin")
+
 	return cmd
 }
 
 func (ac *InitCommand) Execute(cmd *cobra.Command, _ []string) error {
-	auth := &Context{}
+	profile, err := ProfileFromContext(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	auth := &Context{
+		Profile:    profile,
+		Deployment: string(client.DeploymentCloud),
+	}
+	if err = ValidateProfileName(auth.Profile); err != nil {
+		return err
+	}
+
+	if ac.interactive() {
+		err = runInitForm(auth)
+	} else {
+		err = ac.readNonInteractive(cmd, auth)
+	}
+	if err != nil {
+		return err
+	}
+
+	if auth.Deployment == string(client.DeploymentServer) {
+		auth.BaseURL = strings.TrimSuffix(strings.TrimSpace(auth.BaseURL), "/")
+	} else {
+		auth.Subdomain, auth.BaseURL = cloudSite(auth.Subdomain)
+	}
+
+	c, err := newClient(auth)
+	if err != nil {
+		return err
+	}
+
+	user, err := c.Myself.Myself(cmd.Context())
+	if err != nil {
+		return classifyVerifyError(auth, err)
+	}
+	auth.DisplayName = user.DisplayName
+	auth.AccountID = user.AccountID
+
+	if err = auth.Save(); err != nil {
+		return err
+	}
+
+	ac.logger.Info(fmt.Sprintf(
+		"Successfully authenticated as %s, saved credentials as profile %s",
+		user.DisplayName,
+		auth.Profile,
+	))
+	return nil
+}
+
+// interactive reports whether the form should be shown, which is the case
+// when no input was provided through flags or the environment.
+func (ac *InitCommand) interactive() bool {
+	return ac.Site == "" && !ac.TokenStdin && os.Getenv(EnvSite) == "" && os.Getenv(EnvToken) == ""
+}
+
+// readNonInteractive fills `auth` from flags, falling back to environment variables.
+func (ac *InitCommand) readNonInteractive(cmd *cobra.Command, auth *Context) error {
+	deployment, err := client.ParseDeployment(firstNonEmpty(ac.Deployment, os.Getenv(EnvDeployment)))
+	if err != nil {
+		return err
+	}
+	auth.Deployment = string(deployment)
+
+	site := firstNonEmpty(ac.Site, os.Getenv(EnvSite))
+	auth.EmailAddress = firstNonEmpty(ac.Email, os.Getenv(EnvEmail))
+
+	if ac.TokenStdin {
+		data, err := io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return fmt.Errorf("failed to read token from stdin: %w", err)
+		}
+		auth.Token = strings.TrimSpace(string(data))
+	} else {
+		auth.Token = os.Getenv(EnvToken)
+	}
+
+	var missing []string
+	if site == "" {
+		missing = append(missing, "--"+ArgSite+" or "+EnvSite)
+	}
+	if auth.Token == "" {
+		missing = append(missing, "--"+ArgTokenStdin+" or "+EnvToken)
+	}
+	if deployment == client.DeploymentCloud && auth.EmailAddress == "" {
+		missing = append(missing, "--"+ArgEmail+" or "+EnvEmail)
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing %s", strings.Join(missing, ", "))
+	}
+
+	if deployment == client.DeploymentServer {
+		if err = validateBaseURL(site); err != nil {
+			return err
+		}
+		auth.BaseURL = site
+	} else {
+		auth.Subdomain = site
+	}
+
+	return nil
+}
+
+// runInitForm asks the user for the authentication details.
+func runInitForm(auth *Context) error {
+	isServer := func() bool { return auth.Deployment == string(client.DeploymentServer) }
 
 	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Where is your Jira hosted?").
+				Options(
+					huh.NewOption("Jira Cloud (atlassian.net)", string(client.DeploymentCloud)),
+					huh.NewOption("Jira Server or Data Center", string(client.DeploymentServer)),
+				).
+				Value(&auth.Deployment),
+		),
 		huh.NewGroup(
 			huh.NewInput().
 				Title("Enter your email").
 				Value(&auth.EmailAddress),
-		),
+		).WithHideFunc(isServer),
 		huh.NewGroup(
 			huh.NewInput().
 				Title("Enter your Jira subdomain").
+				Description("The part before .atlassian.net, or the full URL of your site").
 				Value(&auth.Subdomain),
-		),
+		).WithHideFunc(isServer),
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Enter the URL of your Jira instance").
+				Placeholder("https://jira.example.com").
+				Validate(validateBaseURL).
+				Value(&auth.BaseURL),
+		).WithHideFunc(func() bool { return !isServer() }),
 		huh.NewGroup(
 			huh.NewInput().
 				EchoMode(huh.EchoModePassword).
 				Title("Enter your API token").
 				Description("You can generate this from your Jira account settings").
 				Value(&auth.Token),
-		),
+		).WithHideFunc(isServer),
+		huh.NewGroup(
+			huh.NewInput().
+				EchoMode(huh.EchoModePassword).
+				Title("Enter your personal access token").
+				Description("You can generate this from your Jira profile").
+				Value(&auth.Token),
+		).WithHideFunc(func() bool { return !isServer() }),
 	)
 
-	err := form.Run()
-	if err != nil {
-		return err
+	return form.Run()
+}
+
+// Token statuses reported after verifying credentials against Jira.
+const (
+	TokenValid       = "valid"
+	TokenRevoked     = "revoked"
+	TokenUnreachable = "unreachable"
+	TokenError       = "error"
+)
+
+// tokenStatus classifies the error returned while verifying credentials.
+func tokenStatus(err error) string {
+	var urlErr *url.Error
+	var oauthErr *oauth.TokenError
+	switch {
+	case err == nil:
+		return TokenValid
+	case client.IsUnauthorized(err), client.IsForbidden(err), errors.As(err, &oauthErr):
+		return TokenRevoked
+	case client.IsNotFound(err), errors.As(err, &urlErr):
+		return TokenUnreachable
+	default:
+		return TokenError
 	}
+}
 
-	c, err := newClient(auth)
-	if err != nil {
+// classifyVerifyError wraps an error returned while verifying the credentials
+// in an ExitError, so scripts can tell bad credentials from connectivity problems.
+func classifyVerifyError(auth *Context, err error) error {
+	switch tokenStatus(err) {
+	case TokenRevoked:
+		return &ExitError{
+			Code: ExitInvalidCredentials,
+			Err:  fmt.Errorf("jira rejected the credentials for %s: %w", auth.URL(), err),
+		}
+	case TokenUnreachable:
+		return &ExitError{
+			Code: ExitUnreachable,
+			Err:  fmt.Errorf("could not reach a Jira instance at %s: %w", auth.URL(), err),
+		}
+	default:
 		return err
 	}
+}
 
-	user, err := c.Myself.Myself(cmd.Context())
-	if err != nil {
-		return err
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
 	}
-	auth.DisplayName = user.DisplayName
 
-	if err = auth.Save(); err != nil {
-		return err
+	return ""
+}
+
+// cloudSite returns the subdomain and base URL for `site`, which is either
+// a subdomain of atlassian.net or the full URL of the site.
+func cloudSite(site string) (string, string) {
+	site = strings.TrimSuffix(strings.TrimSpace(site), "/")
+
+	if !strings.Contains(site, "://") {
+		site = strings.TrimSuffix(site, ".atlassian.net")
+		return site, fmt.Sprintf(client.BaseURLTemplate, site)
+	}
+
+	subdomain := ""
+	if u, err := url.Parse(site); err == nil {
+		subdomain = strings.TrimSuffix(u.Hostname(), ".atlassian.net")
+	}
+
+	return subdomain, site
+}
+
+// validateBaseURL checks that `s` is an absolute http(s) URL.
+func validateBaseURL(s string) error {
+	u, err := url.Parse(strings.TrimSpace(s))
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return errors.New("enter a full URL such as https://jira.example.com")
 	}
 
-	ac.logger.Info(fmt.Sprintf("Successfully authenticated as %s, saved credentials to keyring", user.DisplayName))
 	return nil
 }
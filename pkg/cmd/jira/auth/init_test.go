@@ -0,0 +1,102 @@
+package auth_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MaikelVeen/branch/pkg/cmd/jira/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zalando/go-keyring"
+)
+
+//nolint:paralleltest // the keyring mock and environment are global.
+func TestInitNonInteractive(t *testing.T) {
+	for _, env := range []string{auth.EnvEmail, auth.EnvSite, auth.EnvDeployment, auth.EnvToken} {
+		t.Setenv(env, "")
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Header.Get("Authorization") {
+		case "Bearer valid":
+			assert.Equal(t, "/rest/api/2/myself", r.URL.Path)
+			_, _ = w.Write([]byte(`{"displayName":"Server User","name":"suser"}`))
+		case "Bearer invalid":
+			w.WriteHeader(http.StatusUnauthorized)
+		default:
+			assert.Equal(t, "/rest/api/3/myself", r.URL.Path)
+			_, _ = w.Write([]byte(`{"displayName":"Cloud User","accountId":"abc"}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	run := func(stdin string, args ...string) error {
+		cmd := auth.NewInitCommand()
+		cmd.Command.SetArgs(args)
+		cmd.Command.SetIn(strings.NewReader(stdin))
+		cmd.Command.SilenceUsage = true
+		return cmd.Command.Execute()
+	}
+
+	t.Run("server token from stdin", func(t *testing.T) {
+		keyring.MockInit()
+
+		err := run("valid\n", "--deployment", "server", "--site", srv.URL, "--token-stdin")
+		require.NoError(t, err)
+
+		ctx, err := auth.LoadUserContext(auth.DefaultProfile)
+		require.NoError(t, err)
+		assert.Equal(t, "valid", ctx.Token)
+		assert.Equal(t, srv.URL, ctx.BaseURL)
+		assert.Equal(t, "Server User", ctx.DisplayName)
+	})
+
+	t.Run("cloud credentials from environment", func(t *testing.T) {
+		keyring.MockInit()
+		t.Setenv(auth.EnvSite, srv.URL)
+		t.Setenv(auth.EnvEmail, "me@example.com")
+		t.Setenv(auth.EnvToken, "token")
+
+		require.NoError(t, run(""))
+
+		ctx, err := auth.LoadUserContext(auth.DefaultProfile)
+		require.NoError(t, err)
+		assert.Equal(t, "me@example.com", ctx.EmailAddress)
+		assert.Equal(t, "abc", ctx.AccountID)
+	})
+
+	t.Run("missing email for cloud", func(t *testing.T) {
+		keyring.MockInit()
+
+		err := run("token", "--site", "acme", "--token-stdin")
+		require.ErrorContains(t, err, "--email")
+	})
+
+	t.Run("invalid credentials", func(t *testing.T) {
+		keyring.MockInit()
+
+		err := run("invalid", "--deployment", "server", "--site", srv.URL, "--token-stdin")
+
+		var exitErr *auth.ExitError
+		require.ErrorAs(t, err, &exitErr)
+		assert.Equal(t, auth.ExitInvalidCredentials, exitErr.ExitCode())
+
+		_, err = auth.LoadUserContext(auth.DefaultProfile)
+		require.ErrorIs(t, err, auth.ErrAuthContextMissing, "invalid credentials are not saved")
+	})
+
+	t.Run("unreachable host", func(t *testing.T) {
+		keyring.MockInit()
+
+		closed := httptest.NewServer(http.NotFoundHandler())
+		closed.Close()
+
+		err := run("valid", "--deployment", "server", "--site", closed.URL, "--token-stdin")
+
+		var exitErr *auth.ExitError
+		require.ErrorAs(t, err, &exitErr)
+		assert.Equal(t, auth.ExitUnreachable, exitErr.ExitCode())
+	})
+}
@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/lmittmann/tint"
+	"github.com/spf13/cobra"
+)
+
+type ListCommand struct {
+	Command *cobra.Command
+	logger  *slog.Logger
+}
+
+func NewListCommand() *ListCommand {
+	cmd := &ListCommand{
+		logger: slog.New(
+			tint.NewHandler(os.Stdout, &tint.Options{
+				Level:      slog.LevelInfo,
+				TimeFormat: time.Kitchen,
+			}),
+		),
+	}
+
+	cmd.Command = &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the saved Jira auth profiles",
+		RunE:    cmd.Execute,
+		Args:    cobra.NoArgs,
+	}
+
+	return cmd
+}
+
+func (c *ListCommand) Execute(cmd *cobra.Command, _ []string) error {
+	profiles, active, err := ListProfiles()
+	if err != nil {
+		return err
+	}
+
+	if len(profiles) == 0 {
+		c.logger.Info("No profiles found, run `branch jira auth init` to create one")
+		return nil
+	}
+
+	current, err := ProfileFromContext(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	for _, p := range profiles {
+		marker := " "
+		if p == current {
+			marker = "*"
+		}
+
+		suffix := ""
+		if p == active {
+			suffix = " (active)"
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "%s %s%s\n", marker, p, suffix)
+	}
+
+	return nil
+}
@@ -0,0 +1,246 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/huh"
+	"github.com/lmittmann/tint"
+	"github.com/spf13/cobra"
+
+	client "github.com/MaikelVeen/branch/pkg/jira"
+	"github.com/MaikelVeen/branch/pkg/oauth"
+)
+
+const (
+	ArgClientID     = "client-id"
+	ArgRedirectURL  = "redirect-url"
+	ArgNoBrowser    = "no-browser"
+	ArgAuthURL      = "auth-url"
+	ArgTokenURL     = "token-url"
+	ArgResourcesURL = "resources-url"
+	ArgAPIURL       = "api-url"
+
+	EnvClientID     = "BRANCH_OAUTH_CLIENT_ID"
+	EnvClientSecret = "BRANCH_OAUTH_CLIENT_SECRET"
+
+	loginTimeout = 5 * time.Minute
+)
+
+type LoginCommand struct {
+	Command *cobra.Command
+
+	logger *slog.Logger
+
+	ClientID    string
+	Site        string
+	RedirectURL string
+	NoBrowser   bool
+
+	// Endpoints of the authorization server, overridable to test against a fake server.
+	AuthURL      string
+	TokenURL     string
+	ResourcesURL string
+	// APIURL is the template for the API base URL of a site, given its cloud ID.
+	APIURL string
+
+	// Open is called with the URL of the consent screen.
+	Open func(url string) error
+}
+
+func NewLoginCommand() *LoginCommand {
+	cmd := &LoginCommand{
+		logger: slog.New(
+			tint.NewHandler(os.Stdout, &tint.Options{
+				Level:      slog.LevelInfo,
+				TimeFormat: time.Kitchen,
+			}),
+		),
+	}
+	cmd.Open = cmd.openBrowser
+
+	cmd.Command = &cobra.Command{
+		Use:   "login",
+		Short: "Authenticate with Jira Cloud using OAuth 2.0",
+		Long: "Authenticate with Jira Cloud using OAuth 2.0 instead of an API token. " +
+			"Requires an OAuth 2.0 app created in the Atlassian developer console, with the redirect URL " +
+			"registered as callback URL. The client secret is read from " + EnvClientSecret + ".\n\n" +
+			"The access token is refreshed automatically when it expires.",
+		Example: "  BRANCH_OAUTH_CLIENT_SECRET=... branch jira auth login --client-id abc --site acme",
+		RunE:    cmd.Execute,
+		Args:    cobra.NoArgs,
+	}
+
+	flagset := cmd.Command.Flags()
+	flagset.StringVar(&cmd.ClientID, ArgClientID, "", "Client ID of the OAuth 2.0 app, defaults to "+EnvClientID)
+	flagset.StringVar(&cmd.Site, ArgSite, "", "Site to use when the app has access to more than one")
+	flagset.StringVar(&cmd.RedirectURL, ArgRedirectURL, oauth.DefaultRedirectURL, "Loopback callback URL of the app")
+	flagset.BoolVar(&cmd.NoBrowser, ArgNoBrowser, false, "Print the authorization URL instead of opening a browser")
+
+	flagset.StringVar(&cmd.AuthURL, ArgAuthURL, oauth.DefaultAuthURL, "Authorization endpoint")
+	flagset.StringVar(&cmd.TokenURL, ArgTokenURL, oauth.DefaultTokenURL, "Token endpoint")
+	flagset.StringVar(&cmd.ResourcesURL, ArgResourcesURL, oauth.DefaultResourcesURL, "Accessible resources endpoint")
+	flagset.StringVar(&cmd.APIURL, ArgAPIURL, oauth.APIBaseURLTemplate, "Template of the API base URL of a site")
+	for _, name := range []string{ArgAuthURL, ArgTokenURL, ArgResourcesURL, ArgAPIURL} {
+		_ = flagset.MarkHidden(name)
+	}
+
+	return cmd
+}
+
+func (lc *LoginCommand) Execute(cmd *cobra.Command, _ []string) error {
+	profile, err := ProfileFromContext(cmd.Context())
+	if err != nil {
+		return err
+	}
+	if err = ValidateProfileName(profile); err != nil {
+		return err
+	}
+
+	config := oauth.NewConfig(firstNonEmpty(lc.ClientID, os.Getenv(EnvClientID)), os.Getenv(EnvClientSecret))
+	var missing []string
+	if config.ClientID == "" {
+		missing = append(missing, "--"+ArgClientID+" or "+EnvClientID)
+	}
+	if config.ClientSecret == "" {
+		missing = append(missing, EnvClientSecret)
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing %s", strings.Join(missing, ", "))
+	}
+	config.RedirectURL = lc.RedirectURL
+	config.AuthURL = lc.AuthURL
+	config.TokenURL = lc.TokenURL
+	config.ResourcesURL = lc.ResourcesURL
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), loginTimeout)
+	defer cancel()
+
+	token, err := config.Login(ctx, lc.Open)
+	if err != nil {
+		return err
+	}
+
+	resources, err := config.AccessibleResources(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	resource, err := pickResource(resources, lc.Site)
+	if err != nil {
+		return err
+	}
+
+	auth := &Context{
+		Profile:    profile,
+		Deployment: string(client.DeploymentCloud),
+		BaseURL:    resource.URL,
+		OAuth: &OAuthContext{
+			ClientID:     config.ClientID,
+			ClientSecret: config.ClientSecret,
+			CloudID:      resource.ID,
+			APIURL:       fmt.Sprintf(lc.APIURL, resource.ID),
+			Token:        token,
+		},
+	}
+	if lc.TokenURL != oauth.DefaultTokenURL {
+		auth.OAuth.TokenURL = lc.TokenURL
+	}
+	auth.Subdomain, _ = cloudSite(resource.URL)
+
+	c, err := newClient(auth)
+	if err != nil {
+		return err
+	}
+
+	user, err := c.Myself.Myself(ctx)
+	if err != nil {
+		return classifyVerifyError(auth, err)
+	}
+	auth.DisplayName = user.DisplayName
+	auth.AccountID = user.AccountID
+	auth.EmailAddress = user.EmailAddress
+
+	if err = auth.Save(); err != nil {
+		return err
+	}
+
+	lc.logger.Info(fmt.Sprintf(
+		"Successfully authenticated to %s as %s, saved credentials as profile %s",
+		resource.URL,
+		user.DisplayName,
+		auth.Profile,
+	))
+	return nil
+}
+
+// openBrowser opens `url` in the default browser, printing it when that is not possible.
+func (lc *LoginCommand) openBrowser(url string) error {
+	lc.logger.Info("Open the following URL in your browser to authorize branch: " + url)
+	if lc.NoBrowser {
+		return nil
+	}
+
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+
+	// The URL was printed, so failing to start a browser is not an error.
+	if err := cmd.Start(); err != nil {
+		lc.logger.Debug("Failed to open the browser", "error", err)
+	}
+
+	return nil
+}
+
+// pickResource returns the site matching `site`, which is a name, subdomain or URL.
+// Without `site` the only site is used, or the user is asked when there are more.
+func pickResource(resources []oauth.Resource, site string) (*oauth.Resource, error) {
+	if len(resources) == 0 {
+		return nil, errors.New("the app was not granted access to any Jira site")
+	}
+
+	if site != "" {
+		_, siteURL := cloudSite(site)
+		for i, r := range resources {
+			if strings.EqualFold(r.Name, site) || strings.EqualFold(strings.TrimSuffix(r.URL, "/"), siteURL) {
+				return &resources[i], nil
+			}
+		}
+		return nil, fmt.Errorf("the app was not granted access to site %s", site)
+	}
+
+	if len(resources) == 1 {
+		return &resources[0], nil
+	}
+
+	options := make([]huh.Option[int], len(resources))
+	for i, r := range resources {
+		options[i] = huh.NewOption(fmt.Sprintf("%s (%s)", r.Name, r.URL), i)
+	}
+
+	var selected int
+	err := huh.NewSelect[int]().
+		Title("Select the Jira site").
+		Options(options...).
+		Value(&selected).
+		Run()
+	if err != nil {
+		return nil, err
+	}
+
+	return &resources[selected], nil
+}
@@ -0,0 +1,136 @@
+package auth_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/MaikelVeen/branch/pkg/cmd/jira/auth"
+	"github.com/MaikelVeen/branch/pkg/oauth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zalando/go-keyring"
+)
+
+// newFakeAtlassian returns a server acting as the Atlassian authorization server,
+// accessible resources endpoint and the Jira API of a single site.
+func newFakeAtlassian(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	var challenge string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/authorize", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		challenge = q.Get("code_challenge")
+
+		redirect, _ := url.Parse(q.Get("redirect_uri"))
+		redirect.RawQuery = url.Values{"code": {"code"}, "state": {q.Get("state")}}.Encode()
+		http.Redirect(w, r, redirect.String(), http.StatusFound)
+	})
+	mux.HandleFunc("/oauth/token", func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&req)
+
+		switch {
+		case req["grant_type"] == "authorization_code" && oauth.Challenge(req["code_verifier"]) == challenge:
+			_, _ = w.Write([]byte(`{"access_token":"access","refresh_token":"refresh","expires_in":3600}`))
+		case req["grant_type"] == "refresh_token" && req["refresh_token"] == "refresh":
+			_, _ = w.Write([]byte(`{"access_token":"refreshed","refresh_token":"rotated","expires_in":3600}`))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+		}
+	})
+	mux.HandleFunc("/resources", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`[{"id":"one","url":"https://one.atlassian.net","name":"one"},` +
+			`{"id":"two","url":"https://two.atlassian.net","name":"two"}]`))
+	})
+	mux.HandleFunc("/ex/jira/two/rest/api/3/myself", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Header.Get("Authorization") {
+		case "Bearer access", "Bearer refreshed":
+			_, _ = w.Write([]byte(`{"displayName":"Jane","accountId":"abc","emailAddress":"jane@two.com"}`))
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+//nolint:paralleltest // the keyring mock and environment are global.
+func TestLogin(t *testing.T) {
+	keyring.MockInit()
+	t.Setenv(auth.EnvClientID, "")
+	t.Setenv(auth.EnvClientSecret, "secret")
+
+	srv := newFakeAtlassian(t)
+
+	cmd := auth.NewLoginCommand()
+	cmd.Open = func(authURL string) error {
+		resp, err := http.Get(authURL) //nolint:noctx // follows the redirects like a browser.
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	cmd.Command.SetArgs([]string{
+		"--client-id", "client",
+		"--site", "two",
+		"--redirect-url", "http://127.0.0.1:0/callback",
+		"--auth-url", srv.URL + "/authorize",
+		"--token-url", srv.URL + "/oauth/token",
+		"--resources-url", srv.URL + "/resources",
+		"--api-url", srv.URL + "/ex/jira/%s",
+	})
+	cmd.Command.SilenceUsage = true
+	require.NoError(t, cmd.Command.ExecuteContext(context.Background()))
+
+	authCtx, err := auth.LoadUserContext(auth.DefaultProfile)
+	require.NoError(t, err)
+	assert.Equal(t, "oauth", authCtx.AuthMethod())
+	assert.Equal(t, "https://two.atlassian.net", authCtx.URL())
+	assert.Equal(t, "two", authCtx.Subdomain)
+	assert.Equal(t, "Jane", authCtx.DisplayName)
+	assert.Equal(t, "jane@two.com", authCtx.EmailAddress)
+	require.NotNil(t, authCtx.OAuth)
+	assert.Equal(t, "two", authCtx.OAuth.CloudID)
+	assert.Equal(t, "refresh", authCtx.OAuth.Token.RefreshToken)
+
+	t.Run("expired token is refreshed and saved", func(t *testing.T) {
+		authCtx.OAuth.Token.Expiry = time.Now().Add(-time.Minute)
+
+		c, err := auth.NewClientFromContext(context.WithValue(context.Background(), auth.DefaultContextKey, authCtx))
+		require.NoError(t, err)
+
+		_, err = c.Myself.Myself(context.Background())
+		require.NoError(t, err)
+
+		saved, err := auth.LoadUserContext(auth.DefaultProfile)
+		require.NoError(t, err)
+		assert.Equal(t, "refreshed", saved.OAuth.Token.AccessToken)
+		assert.Equal(t, "rotated", saved.OAuth.Token.RefreshToken)
+	})
+}
+
+//nolint:paralleltest // the environment is global.
+func TestLoginMissingClient(t *testing.T) {
+	t.Setenv(auth.EnvClientID, "")
+	t.Setenv(auth.EnvClientSecret, "")
+
+	cmd := auth.NewLoginCommand()
+	cmd.Command.SetArgs([]string{})
+	cmd.Command.SilenceUsage = true
+
+	err := cmd.Command.ExecuteContext(context.Background())
+	require.ErrorContains(t, err, auth.EnvClientSecret)
+}
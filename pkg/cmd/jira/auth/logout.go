@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/lmittmann/tint"
+	"github.com/spf13/cobra"
+
+	"github.com/MaikelVeen/branch/pkg/credentials"
+)
+
+type LogoutCommand struct {
+	Command *cobra.Command
+	logger  *slog.Logger
+}
+
+func NewLogoutCommand() *LogoutCommand {
+	cmd := &LogoutCommand{
+		logger: slog.New(
+			tint.NewHandler(os.Stdout, &tint.Options{
+				Level:      slog.LevelInfo,
+				TimeFormat: time.Kitchen,
+			}),
+		),
+	}
+
+	cmd.Command = &cobra.Command{
+		Use:   "logout",
+		Short: "Delete the stored credentials of the current profile",
+		RunE:  cmd.Execute,
+		Args:  cobra.NoArgs,
+	}
+
+	return cmd
+}
+
+func (c *LogoutCommand) Execute(cmd *cobra.Command, _ []string) error {
+	profile, err := ProfileFromContext(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	// The credentials are deleted even when the profile is not in the index,
+	// such as credentials saved before profiles existed.
+	err = store.Delete(profileKeyringUserFor(profile))
+	if err != nil && !errors.Is(err, credentials.ErrNotFound) {
+		return err
+	}
+	deleted := err == nil
+
+	if err = RemoveProfile(profile); err != nil && !errors.Is(err, ErrProfileNotFound) {
+		return err
+	}
+
+	if !deleted && err != nil {
+		c.logger.Info(fmt.Sprintf("Not logged in with profile %s", profile))
+		return nil
+	}
+
+	c.logger.Info(fmt.Sprintf("Logged out, deleted the credentials of profile %s", profile))
+	return nil
+}
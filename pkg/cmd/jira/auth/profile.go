@@ -0,0 +1,220 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"slices"
+
+	"github.com/MaikelVeen/branch/pkg/credentials"
+	"github.com/MaikelVeen/branch/pkg/git"
+)
+
+const (
+	// DefaultProfile is the profile used when none is selected. It is stored
+	// under the keyring entry used before profiles existed.
+	DefaultProfile = "default"
+
+	// ProfileEnv is the environment variable that selects a profile.
+	ProfileEnv = "BRANCH_PROFILE"
+	// RepoProfileKey is the git config key that selects a profile for a repository.
+	RepoProfileKey = "jira.profile"
+
+	profilesKeyringUser = "profiles"
+	profileKeyringUser  = "profile:"
+)
+
+var (
+	ErrProfileNotFound = errors.New("profile not found")
+
+	profileNameRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+)
+
+// profileIndex keeps track of the saved profiles, as credential stores can not be enumerated.
+type profileIndex struct {
+	Active   string   `json:"active"`
+	Profiles []string `json:"profiles"`
+}
+
+// ValidateProfileName returns an error when `name` can not be used as a profile name.
+func ValidateProfileName(name string) error {
+	if !profileNameRe.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q, use letters, digits, '.', '_' and '-'", name)
+	}
+
+	return nil
+}
+
+// ResolveProfile returns the name of the profile to use. In order of precedence
+// it is taken from `flag`, the BRANCH_PROFILE environment variable, the jira.profile
+// git config of the current repository and the active profile.
+func ResolveProfile(flag string) (string, error) {
+	if flag != "" {
+		return flag, nil
+	}
+
+	if env := os.Getenv(ProfileEnv); env != "" {
+		return env, nil
+	}
+
+	// Errors are expected outside of a repository or when the key is not set.
+	if repo, err := git.NewCommander().Config(exec.Command, "--get", RepoProfileKey); err == nil && repo != "" {
+		return repo, nil
+	}
+
+	index, err := loadProfileIndex()
+	if err != nil {
+		return "", err
+	}
+
+	return index.Active, nil
+}
+
+// ProfileFromContext returns the profile selected in `ctx`, or the default profile.
+func ProfileFromContext(ctx context.Context) (string, error) {
+	switch v := ctx.Value(DefaultContextKey).(type) {
+	case *session:
+		profile, err := v.resolveProfile()
+		if err != nil || profile != "" {
+			return profile, err
+		}
+	case *Context:
+		if v.Profile != "" {
+			return v.Profile, nil
+		}
+	}
+
+	return DefaultProfile, nil
+}
+
+// ListProfiles returns the names of the saved profiles and the active profile.
+func ListProfiles() ([]string, string, error) {
+	index, err := loadProfileIndex()
+	if err != nil {
+		return nil, "", err
+	}
+
+	return index.Profiles, index.Active, nil
+}
+
+// UseProfile makes `name` the active profile.
+func UseProfile(name string) error {
+	index, err := loadProfileIndex()
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(index.Profiles, name) {
+		return fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+	}
+
+	index.Active = name
+	return index.save()
+}
+
+// RemoveProfile deletes the credentials of profile `name`. When it was the active
+// profile, the first remaining profile becomes active.
+func RemoveProfile(name string) error {
+	index, err := loadProfileIndex()
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(index.Profiles, name) {
+		return fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+	}
+
+	if err = store.Delete(profileKeyringUserFor(name)); err != nil && !errors.Is(err, credentials.ErrNotFound) {
+		return err
+	}
+
+	index.Profiles = slices.DeleteFunc(index.Profiles, func(p string) bool { return p == name })
+	if index.Active == name {
+		index.Active = DefaultProfile
+		if len(index.Profiles) > 0 {
+			index.Active = index.Profiles[0]
+		}
+	}
+
+	return index.save()
+}
+
+// addProfile records `name` in the profile index. The first saved profile becomes active.
+func addProfile(name string) error {
+	index, err := loadProfileIndex()
+	if err != nil {
+		return err
+	}
+
+	if slices.Contains(index.Profiles, name) {
+		return nil
+	}
+
+	if len(index.Profiles) == 0 {
+		index.Active = name
+	}
+
+	index.Profiles = append(index.Profiles, name)
+	slices.Sort(index.Profiles)
+	return index.save()
+}
+
+func loadProfileIndex() (*profileIndex, error) {
+	data, err := store.Get(profilesKeyringUser)
+	if err != nil {
+		if !errors.Is(err, credentials.ErrNotFound) {
+			return nil, err
+		}
+
+		return legacyProfileIndex()
+	}
+
+	index := &profileIndex{}
+	if err = json.Unmarshal([]byte(data), index); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal profile index: %w", err)
+	}
+
+	if index.Active == "" {
+		index.Active = DefaultProfile
+	}
+
+	return index, nil
+}
+
+// legacyProfileIndex returns the index for a store that was written before
+// profiles existed, which contains at most the default profile.
+func legacyProfileIndex() (*profileIndex, error) {
+	index := &profileIndex{Active: DefaultProfile}
+
+	_, err := store.Get(keyringUser)
+	switch {
+	case err == nil:
+		index.Profiles = []string{DefaultProfile}
+	case !errors.Is(err, credentials.ErrNotFound):
+		return nil, err
+	}
+
+	return index, nil
+}
+
+func (i *profileIndex) save() error {
+	data, err := json.Marshal(i)
+	if err != nil {
+		return err
+	}
+
+	return store.Set(profilesKeyringUser, string(data))
+}
+
+// profileKeyringUserFor returns the key under which profile `name` is stored.
+func profileKeyringUserFor(name string) string {
+	if name == DefaultProfile {
+		return keyringUser
+	}
+
+	return profileKeyringUser + name
+}
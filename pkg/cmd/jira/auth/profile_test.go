@@ -0,0 +1,82 @@
+package auth_test
+
+import (
+	"testing"
+
+	"github.com/MaikelVeen/branch/pkg/cmd/jira/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zalando/go-keyring"
+)
+
+func TestValidateProfileName(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{"default", "acme", "client-a", "client_b.prod", "2024"} {
+		require.NoError(t, auth.ValidateProfileName(name), name)
+	}
+
+	for _, name := range []string{"", "-acme", ".acme", "acme corp", "acme/prod", "profiles:x"} {
+		require.Error(t, auth.ValidateProfileName(name), name)
+	}
+}
+
+//nolint:paralleltest // the keyring mock and environment are global.
+func TestProfiles(t *testing.T) {
+	keyring.MockInit()
+	t.Setenv(auth.ProfileEnv, "")
+
+	profiles, active, err := auth.ListProfiles()
+	require.NoError(t, err)
+	assert.Empty(t, profiles)
+	assert.Equal(t, auth.DefaultProfile, active)
+
+	require.NoError(t, (&auth.Context{Profile: "acme", Token: "a"}).Save())
+	require.NoError(t, (&auth.Context{Profile: "beta", Token: "b"}).Save())
+
+	profiles, active, err = auth.ListProfiles()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acme", "beta"}, profiles)
+	assert.Equal(t, "acme", active, "the first saved profile becomes active")
+
+	require.NoError(t, auth.UseProfile("beta"))
+	require.ErrorIs(t, auth.UseProfile("unknown"), auth.ErrProfileNotFound)
+
+	resolved, err := auth.ResolveProfile("acme")
+	require.NoError(t, err)
+	assert.Equal(t, "acme", resolved, "the flag takes precedence")
+
+	t.Setenv(auth.ProfileEnv, "from-env")
+	resolved, err = auth.ResolveProfile("")
+	require.NoError(t, err)
+	assert.Equal(t, "from-env", resolved)
+
+	ctx, err := auth.LoadUserContext("beta")
+	require.NoError(t, err)
+	assert.Equal(t, "b", ctx.Token)
+	assert.Equal(t, "beta", ctx.Profile)
+
+	require.NoError(t, auth.RemoveProfile("beta"))
+	_, err = auth.LoadUserContext("beta")
+	require.ErrorIs(t, err, auth.ErrAuthContextMissing)
+
+	profiles, active, err = auth.ListProfiles()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acme"}, profiles)
+	assert.Equal(t, "acme", active)
+}
+
+//nolint:paralleltest // the keyring mock is global.
+func TestLegacyContextIsDefaultProfile(t *testing.T) {
+	keyring.MockInit()
+	require.NoError(t, keyring.Set("branch_jira", "branch", `{"subdomain":"legacy","token":"t"}`))
+
+	profiles, active, err := auth.ListProfiles()
+	require.NoError(t, err)
+	assert.Equal(t, []string{auth.DefaultProfile}, profiles)
+	assert.Equal(t, auth.DefaultProfile, active)
+
+	ctx, err := auth.LoadUserContext("")
+	require.NoError(t, err)
+	assert.Equal(t, "https://legacy.atlassian.net", ctx.URL())
+}
@@ -0,0 +1,46 @@
+package auth
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/lmittmann/tint"
+	"github.com/spf13/cobra"
+)
+
+type RemoveCommand struct {
+	Command *cobra.Command
+	logger  *slog.Logger
+}
+
+func NewRemoveCommand() *RemoveCommand {
+	cmd := &RemoveCommand{
+		logger: slog.New(
+			tint.NewHandler(os.Stdout, &tint.Options{
+				Level:      slog.LevelInfo,
+				TimeFormat: time.Kitchen,
+			}),
+		),
+	}
+
+	cmd.Command = &cobra.Command{
+		Use:     "remove <profile>",
+		Aliases: []string{"rm"},
+		Short:   "Remove a Jira auth profile and its credentials",
+		RunE:    cmd.Execute,
+		Args:    cobra.ExactArgs(1),
+	}
+
+	return cmd
+}
+
+func (c *RemoveCommand) Execute(_ *cobra.Command, args []string) error {
+	if err := RemoveProfile(args[0]); err != nil {
+		return err
+	}
+
+	c.logger.Info(fmt.Sprintf("Removed profile %s", args[0]))
+	return nil
+}
@@ -1,8 +1,8 @@
 package auth
 
 import (
+	"encoding/json"
 	"errors"
-	"fmt"
 	"log/slog"
 	"os"
 	"time"
@@ -11,9 +11,29 @@ import (
 	"github.com/spf13/cobra"
 )
 
+const (
+	ArgJSON = "json"
+)
+
 type ShowCommand struct {
 	Command *cobra.Command
 	logger  *slog.Logger
+
+	JSON bool
+}
+
+// ShowOutput is the output of `auth show --json`.
+type ShowOutput struct {
+	Profile     string `json:"profile"`
+	Site        string `json:"site"`
+	Deployment  string `json:"deployment"`
+	Auth        string `json:"auth"`
+	Email       string `json:"email,omitempty"`
+	DisplayName string `json:"displayName,omitempty"`
+	AccountID   string `json:"accountId,omitempty"`
+	TimeZone    string `json:"timeZone,omitempty"`
+	Status      string `json:"status"`
+	Error       string `json:"error,omitempty"`
 }
 
 func NewShowCommand() *ShowCommand {
@@ -28,24 +48,98 @@ func NewShowCommand() *ShowCommand {
 
 	cmd.Command = &cobra.Command{
 		Use:   "show",
-		Short: "Display the current Jira auth context",
+		Short: "Display the current Jira auth context and verify the token",
 		RunE:  cmd.Execute,
 		Args:  cobra.NoArgs,
 	}
 
+	cmd.Command.Flags().BoolVar(&cmd.JSON, ArgJSON, false, "Output as JSON")
+
 	return cmd
 }
 
-func (cmd *ShowCommand) Execute(_ *cobra.Command, _ []string) error {
-	auth, err := LoadUserContext()
+func (cmd *ShowCommand) Execute(cc *cobra.Command, _ []string) error {
+	profile, err := ProfileFromContext(cc.Context())
+	if err != nil {
+		return err
+	}
+
+	auth, err := LoadUserContext(profile)
 	if err != nil {
 		if errors.Is(err, ErrAuthContextMissing) {
-			cmd.logger.Info("No authentication context found")
+			cmd.logger.Info("No authentication context found", "profile", profile)
 			return nil
 		}
-		cmd.logger.Error(err.Error())
+		return err
+	}
+
+	out := ShowOutput{
+		Profile:     profile,
+		Site:        auth.URL(),
+		Deployment:  auth.Deployment,
+		Auth:        auth.AuthMethod(),
+		Email:       auth.EmailAddress,
+		DisplayName: auth.DisplayName,
+		AccountID:   auth.AccountID,
+	}
+	if out.Deployment == "" {
+		out.Deployment = "cloud"
+	}
+
+	c, err := newClient(auth)
+	if err != nil {
+		return err
+	}
+
+	user, err := c.Myself.Myself(cc.Context())
+	out.Status = tokenStatus(err)
+	if err != nil {
+		out.Error = err.Error()
+	} else {
+		out.DisplayName = user.DisplayName
+		out.AccountID = user.AccountID
+		out.TimeZone = user.TimeZone
+		if user.EmailAddress != "" {
+			out.Email = user.EmailAddress
+		}
+	}
+
+	if cmd.JSON {
+		enc := json.NewEncoder(cc.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
+	attrs := []any{
+		"profile", out.Profile,
+		"site", out.Site,
+		"deployment", out.Deployment,
+		"auth", out.Auth,
+		"email", out.Email,
+		"accountId", out.AccountID,
+		"timeZone", out.TimeZone,
+	}
+
+	switch out.Status {
+	case TokenValid:
+		cmd.logger.Info("Authenticated as "+out.DisplayName, attrs...)
+	case TokenRevoked:
+		cmd.logger.Error("The token is invalid or has been revoked, run `branch jira auth "+reauthCommand(auth)+"` to authenticate again",
+			append(attrs, "error", out.Error)...)
+	case TokenUnreachable:
+		cmd.logger.Warn("Jira could not be reached, the token could not be verified", append(attrs, "error", out.Error)...)
+	default:
+		cmd.logger.Warn("The token could not be verified", append(attrs, "error", out.Error)...)
 	}
 
-	cmd.logger.Info(fmt.Sprintf("Authenticated as %s(%s)", auth.DisplayName, auth.EmailAddress))
 	return nil
 }
+
+// reauthCommand returns the auth subcommand that recreates `auth`.
+func reauthCommand(auth *Context) string {
+	if auth.OAuth != nil {
+		return "login"
+	}
+
+	return "init"
+}
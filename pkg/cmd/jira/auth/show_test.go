@@ -0,0 +1,99 @@
+package auth_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MaikelVeen/branch/pkg/cmd/jira/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zalando/go-keyring"
+)
+
+//nolint:paralleltest // the keyring mock is global.
+func TestShowJSON(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer valid" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"displayName":"Jane","accountId":"abc","timeZone":"Europe/Amsterdam"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	testCases := map[string]struct {
+		url    string
+		token  string
+		status string
+	}{
+		"valid token":   {url: srv.URL, token: "valid", status: auth.TokenValid},
+		"revoked token": {url: srv.URL, token: "revoked", status: auth.TokenRevoked},
+		"unreachable":   {url: closed.URL, token: "valid", status: auth.TokenUnreachable},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			keyring.MockInit()
+			require.NoError(t, (&auth.Context{Deployment: "server", BaseURL: tc.url, Token: tc.token}).Save())
+
+			var out bytes.Buffer
+			cmd := auth.NewShowCommand()
+			cmd.Command.SetArgs([]string{"--json"})
+			cmd.Command.SetOut(&out)
+			require.NoError(t, cmd.Command.ExecuteContext(context.Background()))
+
+			var got auth.ShowOutput
+			require.NoError(t, json.Unmarshal(out.Bytes(), &got))
+			assert.Equal(t, tc.status, got.Status)
+			assert.Equal(t, auth.DefaultProfile, got.Profile)
+			assert.Equal(t, tc.url, got.Site)
+
+			if tc.status == auth.TokenValid {
+				assert.Equal(t, "abc", got.AccountID)
+				assert.Equal(t, "Europe/Amsterdam", got.TimeZone)
+			} else {
+				assert.NotEmpty(t, got.Error)
+			}
+		})
+	}
+}
+
+//nolint:paralleltest // the keyring mock is global.
+func TestLogout(t *testing.T) {
+	keyring.MockInit()
+	require.NoError(t, (&auth.Context{Token: "token"}).Save())
+
+	cmd := auth.NewLogoutCommand()
+	cmd.Command.SetArgs([]string{})
+	require.NoError(t, cmd.Command.ExecuteContext(context.Background()))
+
+	_, err := auth.LoadUserContext(auth.DefaultProfile)
+	require.ErrorIs(t, err, auth.ErrAuthContextMissing)
+
+	// Logging out twice is not an error.
+	require.NoError(t, cmd.Command.ExecuteContext(context.Background()))
+}
+
+//nolint:paralleltest // the keyring mock is global.
+func TestLogoutProfileMissingFromIndex(t *testing.T) {
+	keyring.MockInit()
+	require.NoError(t, keyring.Set("branch_jira", "profile:acme", `{"subdomain":"acme","token":"t"}`))
+
+	profiles, _, err := auth.ListProfiles()
+	require.NoError(t, err)
+	require.NotContains(t, profiles, "acme")
+
+	cmd := auth.NewLogoutCommand()
+	cmd.Command.SetArgs([]string{})
+	require.NoError(t, cmd.Command.ExecuteContext(auth.WithSession(context.Background(), "acme")))
+
+	_, err = auth.LoadUserContext("acme")
+	require.ErrorIs(t, err, auth.ErrAuthContextMissing)
+}
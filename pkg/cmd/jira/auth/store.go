@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/MaikelVeen/branch/pkg/config"
+	"github.com/MaikelVeen/branch/pkg/credentials"
+	"github.com/charmbracelet/huh"
+
+	client "github.com/MaikelVeen/branch/pkg/jira"
+)
+
+const (
+	// PassphraseEnv is the environment variable holding the passphrase of the encrypted credential file.
+	PassphraseEnv = "BRANCH_CREDENTIALS_PASSPHRASE"
+
+	credentialsFilename = "credentials.age"
+)
+
+// store is where auth contexts and the profile index are persisted.
+var store credentials.Store = credentials.NewKeyringStore(keyringService)
+
+// SetStore replaces the store used to persist auth contexts.
+func SetStore(s credentials.Store) {
+	store = s
+}
+
+// NewStore returns the credential store selected by `cfg`.
+func NewStore(cfg *config.Config) (credentials.Store, error) {
+	switch name := cfg.CredentialStore(); name {
+	case config.CredentialStoreKeyring:
+		return credentials.NewKeyringStore(keyringService), nil
+	case config.CredentialStoreFile:
+		dir, err := config.Dir()
+		if err != nil {
+			return nil, err
+		}
+		return credentials.NewFileStore(filepath.Join(dir, credentialsFilename), passphrase), nil
+	case config.CredentialStoreEnv:
+		return envStore{}, nil
+	case config.CredentialStoreHelper:
+		if cfg.Credentials.Helper == nil || *cfg.Credentials.Helper == "" {
+			return nil, fmt.Errorf("the helper credential store requires %s to be set", config.KeyCredentialHelper)
+		}
+		return credentials.NewHelperStore(*cfg.Credentials.Helper), nil
+	default:
+		return nil, fmt.Errorf("unknown credential store %q", name)
+	}
+}
+
+// passphrase returns the passphrase of the encrypted credential file from
+// the environment, or asks for it.
+func passphrase() (string, error) {
+	if p := os.Getenv(PassphraseEnv); p != "" {
+		return p, nil
+	}
+
+	var p string
+	err := huh.NewInput().
+		EchoMode(huh.EchoModePassword).
+		Title("Enter the passphrase of the credential file").
+		Description(fmt.Sprintf("Set %s to skip this prompt", PassphraseEnv)).
+		Value(&p).
+		Run()
+
+	return p, err
+}
+
+// envStore is a read-only store that builds the auth context from the same
+// environment variables that `auth init` accepts. Every profile resolves to it.
+type envStore struct{}
+
+func (envStore) Get(key string) (string, error) {
+	token := os.Getenv(EnvToken)
+	if key == profilesKeyringUser || token == "" {
+		return "", credentials.ErrNotFound
+	}
+
+	deployment, err := client.ParseDeployment(os.Getenv(EnvDeployment))
+	if err != nil {
+		return "", err
+	}
+
+	auth := Context{
+		Deployment:   string(deployment),
+		EmailAddress: os.Getenv(EnvEmail),
+		Token:        token,
+	}
+
+	if deployment == client.DeploymentServer {
+		auth.BaseURL = os.Getenv(EnvSite)
+	} else {
+		auth.Subdomain, auth.BaseURL = cloudSite(os.Getenv(EnvSite))
+	}
+
+	data, err := json.Marshal(auth)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+func (envStore) Set(string, string) error {
+	return fmt.Errorf("%w: credentials are read from %s, %s, %s and %s",
+		credentials.ErrReadOnly, EnvSite, EnvEmail, EnvDeployment, EnvToken)
+}
+
+func (envStore) Delete(string) error {
+	return credentials.ErrReadOnly
+}
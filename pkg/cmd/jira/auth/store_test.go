@@ -0,0 +1,101 @@
+package auth_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/MaikelVeen/branch/pkg/cmd/jira/auth"
+	"github.com/MaikelVeen/branch/pkg/config"
+	"github.com/MaikelVeen/branch/pkg/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewStore(t *testing.T) {
+	t.Parallel()
+
+	store := func(name string, helper *string) (credentials.Store, error) {
+		return auth.NewStore(&config.Config{Credentials: config.CredentialsConfig{Store: &name, Helper: helper}})
+	}
+
+	s, err := auth.NewStore(&config.Config{})
+	require.NoError(t, err)
+	assert.IsType(t, &credentials.KeyringStore{}, s, "the keyring is the default")
+
+	s, err = store(config.CredentialStoreFile, nil)
+	require.NoError(t, err)
+	assert.IsType(t, &credentials.FileStore{}, s)
+
+	_, err = store(config.CredentialStoreHelper, nil)
+	require.Error(t, err, "the helper store needs a helper")
+
+	helper := "pass"
+	s, err = store(config.CredentialStoreHelper, &helper)
+	require.NoError(t, err)
+	assert.IsType(t, &credentials.HelperStore{}, s)
+
+	_, err = store("vault", nil)
+	require.Error(t, err)
+}
+
+//nolint:paralleltest // the store and environment are global.
+func TestEnvStore(t *testing.T) {
+	name := config.CredentialStoreEnv
+	s, err := auth.NewStore(&config.Config{Credentials: config.CredentialsConfig{Store: &name}})
+	require.NoError(t, err)
+
+	auth.SetStore(s)
+	t.Cleanup(func() { auth.SetStore(credentials.NewKeyringStore("branch_jira")) })
+	t.Setenv(auth.EnvToken, "")
+	_, err = auth.LoadUserContext(auth.DefaultProfile)
+	require.ErrorIs(t, err, auth.ErrAuthContextMissing)
+
+	t.Setenv(auth.EnvSite, "acme")
+	t.Setenv(auth.EnvEmail, "me@acme.com")
+	t.Setenv(auth.EnvDeployment, "")
+	t.Setenv(auth.EnvToken, "token")
+
+	ctx, err := auth.LoadUserContext("any")
+	require.NoError(t, err)
+	assert.Equal(t, "https://acme.atlassian.net", ctx.URL())
+	assert.Equal(t, "me@acme.com", ctx.EmailAddress)
+	assert.Equal(t, "token", ctx.Token)
+
+	require.ErrorIs(t, ctx.Save(), credentials.ErrReadOnly)
+}
+
+//nolint:paralleltest // the store and environment are global.
+func TestSessionReadsStoreWhenUsed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.age")
+	prompts := 0
+	passphrase := func() (string, error) {
+		prompts++
+		return "secret", nil
+	}
+
+	auth.SetStore(credentials.NewFileStore(path, passphrase))
+	t.Cleanup(func() { auth.SetStore(credentials.NewKeyringStore("branch_jira")) })
+	require.NoError(t, (&auth.Context{Profile: "acme", Deployment: "server", BaseURL: "https://jira.acme.com", Token: "t"}).Save())
+
+	prompts = 0
+	auth.SetStore(credentials.NewFileStore(path, passphrase))
+	ctx := auth.WithSession(context.Background(), "acme")
+
+	profile, err := auth.ProfileFromContext(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "acme", profile)
+	assert.Zero(t, prompts, "the profile flag does not need the store")
+
+	_, err = auth.NewClientFromContext(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, prompts)
+
+	authCtx, ok := auth.FromContext(ctx)
+	require.True(t, ok)
+	assert.Equal(t, "acme", authCtx.Profile)
+	assert.Equal(t, 1, prompts, "the auth context is loaded once")
+
+	_, err = auth.NewClientFromContext(auth.WithSession(context.Background(), "unknown"))
+	require.Error(t, err)
+}
@@ -0,0 +1,45 @@
+package auth
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/lmittmann/tint"
+	"github.com/spf13/cobra"
+)
+
+type UseCommand struct {
+	Command *cobra.Command
+	logger  *slog.Logger
+}
+
+func NewUseCommand() *UseCommand {
+	cmd := &UseCommand{
+		logger: slog.New(
+			tint.NewHandler(os.Stdout, &tint.Options{
+				Level:      slog.LevelInfo,
+				TimeFormat: time.Kitchen,
+			}),
+		),
+	}
+
+	cmd.Command = &cobra.Command{
+		Use:   "use <profile>",
+		Short: "Set the active Jira auth profile",
+		RunE:  cmd.Execute,
+		Args:  cobra.ExactArgs(1),
+	}
+
+	return cmd
+}
+
+func (c *UseCommand) Execute(_ *cobra.Command, args []string) error {
+	if err := UseProfile(args[0]); err != nil {
+		return err
+	}
+
+	c.logger.Info(fmt.Sprintf("Switched to profile %s", args[0]))
+	return nil
+}
@@ -18,5 +18,6 @@ func NewCommand() *Command {
 	}
 
 	jc.Command.AddCommand(auth.NewCommand().Command)
+	jc.Command.AddCommand(NewTransitionCommand().Command)
 	return jc
 }
@@ -0,0 +1,110 @@
+package jira
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/MaikelVeen/branch/pkg/cmd/jira/auth"
+	"github.com/MaikelVeen/branch/pkg/jira"
+	"github.com/charmbracelet/huh"
+	"github.com/lmittmann/tint"
+	"github.com/spf13/cobra"
+)
+
+// TransitionCommand moves an issue through its workflow.
+type TransitionCommand struct {
+	Command *cobra.Command
+	logger  *slog.Logger
+}
+
+func NewTransitionCommand() *TransitionCommand {
+	cmd := &TransitionCommand{
+		logger: slog.New(
+			tint.NewHandler(os.Stdout, &tint.Options{
+				Level:      slog.LevelInfo,
+				TimeFormat: time.Kitchen,
+			}),
+		),
+	}
+
+	cmd.Command = &cobra.Command{
+		Use:     "transition <key> [status]",
+		Aliases: []string{"tr"},
+		Short:   "Move an issue to another status",
+		Long: "Move an issue through its workflow. The status can be given as the name of a transition " +
+			"or the name of the target status, when omitted a picker with the available transitions is shown.",
+		Args: cobra.RangeArgs(1, 2),
+		RunE: cmd.Execute,
+	}
+
+	return cmd
+}
+
+func (c *TransitionCommand) Execute(cmd *cobra.Command, args []string) error {
+	client, err := auth.NewClientFromContext(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	key := args[0]
+	issue, err := client.Issue.GetIssue(cmd.Context(), key)
+	if err != nil {
+		return err
+	}
+
+	transitions, err := client.Issue.GetTransitions(cmd.Context(), key)
+	if err != nil {
+		return err
+	}
+
+	if len(transitions) == 0 {
+		return fmt.Errorf("no transitions are available for %s from %s", key, issue.Fields.Status.Name)
+	}
+
+	var transition *jira.Transition
+	if len(args) > 1 {
+		transition, err = jira.FindTransition(transitions, args[1])
+	} else {
+		transition, err = pickTransition(issue, transitions)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err = transition.Validate(); err != nil {
+		return err
+	}
+
+	if err = client.Issue.DoTransition(cmd.Context(), key, transition.ID, nil); err != nil {
+		return err
+	}
+
+	c.logger.Info(fmt.Sprintf("moved %s from %s to %s", key, issue.Fields.Status.Name, transition.To.Name))
+	return nil
+}
+
+// pickTransition shows a picker with the transitions available for `issue`.
+func pickTransition(issue *jira.Issue, transitions []jira.Transition) (*jira.Transition, error) {
+	options := make([]huh.Option[int], 0, len(transitions))
+	for i, t := range transitions {
+		label := t.Name
+		if t.To.Name != "" && t.To.Name != t.Name {
+			label = fmt.Sprintf("%s → %s", t.Name, t.To.Name)
+		}
+		options = append(options, huh.NewOption(label, i))
+	}
+
+	var selected int
+	if err := huh.NewSelect[int]().
+		Title(fmt.Sprintf("Move %s", issue.Key)).
+		Description(fmt.Sprintf("Current status: %s", issue.Fields.Status.Name)).
+		Options(options...).
+		Value(&selected).
+		Run(); err != nil {
+		return nil, err
+	}
+
+	return &transitions[selected], nil
+}
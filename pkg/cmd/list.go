@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/MaikelVeen/branch/pkg/cmd/jira/auth"
+	"github.com/MaikelVeen/branch/pkg/git"
+	"github.com/MaikelVeen/branch/pkg/jira"
+	"github.com/lmittmann/tint"
+	"github.com/spf13/cobra"
+)
+
+const (
+	ArgJSON   = "json"
+	ArgStatus = "status"
+)
+
+type ListCommand struct {
+	Command *cobra.Command
+
+	logger *slog.Logger
+	git    *git.Commander
+
+	BaseBranch string
+	JSON       bool
+	Statuses   []string
+}
+
+func NewListCommand() *ListCommand {
+	lc := &ListCommand{
+		logger: slog.New(
+			tint.NewHandler(os.Stdout, &tint.Options{
+				Level:      slog.LevelInfo,
+				TimeFormat: time.Kitchen,
+			}),
+		),
+		git: git.NewCommander(),
+	}
+
+	lc.Command = &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "Lists local branches with the status of their Jira issues",
+		Example: "  branch list --status done",
+		Args:    cobra.NoArgs,
+		RunE:    lc.Execute,
+	}
+
+	flagset := lc.Command.Flags()
+	flagset.StringVarP(
+		&lc.BaseBranch,
+		ArgBase,
+		ArgBaseShort,
+		"",
+		"Base branch to check whether branches are merged, defaults to the default branch of the remote",
+	)
+	flagset.BoolVar(&lc.JSON, ArgJSON, false, "Output as JSON")
+	flagset.StringSliceVar(
+		&lc.Statuses,
+		ArgStatus,
+		nil,
+		"Only list branches whose issue has this status or status category, can be repeated",
+	)
+
+	return lc
+}
+
+func (c *ListCommand) Execute(cmd *cobra.Command, _ []string) error {
+	// The branches are listed without issue details when not authenticated.
+	client, err := auth.NewClientFromContext(cmd.Context())
+	if err != nil {
+		if !c.JSON {
+			c.logger.Warn("not authenticated with Jira, run `branch jira auth init` to show issue details")
+		}
+		client = nil
+	}
+
+	if c.BaseBranch == "" {
+		if c.BaseBranch, err = defaultBaseBranch(c.git); err != nil && !c.JSON {
+			c.logger.Warn(fmt.Sprintf("%s, using %s", err, c.BaseBranch))
+		}
+	}
+
+	branches, err := collectBranches(cmd.Context(), c.git, client, c.BaseBranch, false)
+	if err != nil {
+		return describeBranchError(err, c.BaseBranch)
+	}
+
+	branches = FilterBranchesByStatus(branches, c.Statuses)
+
+	if c.JSON {
+		if branches == nil {
+			branches = []BranchInfo{}
+		}
+
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(branches)
+	}
+
+	return WriteBranchTable(cmd.OutOrStdout(), branches)
+}
+
+// WriteBranchTable writes `branches` to `w` as an aligned table.
+func WriteBranchTable(w io.Writer, branches []BranchInfo) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  BRANCH\tKEY\tSTATUS\tASSIGNEE\tLAST COMMIT\tMERGED")
+
+	for _, b := range branches {
+		marker := " "
+		if b.Current {
+			marker = "*"
+		}
+
+		lastCommit := ""
+		if !b.LastCommit.IsZero() {
+			lastCommit = b.LastCommit.Local().Format(time.DateOnly)
+		}
+
+		merged := "no"
+		if b.Merged {
+			merged = "yes"
+		}
+
+		fmt.Fprintf(tw, "%s %s\t%s\t%s\t%s\t%s\t%s\n",
+			marker, b.Branch, dash(b.Key), dash(b.Status), dash(b.Assignee), lastCommit, merged)
+	}
+
+	return tw.Flush()
+}
+
+// describeBranchError explains an error returned while collecting branches.
+func describeBranchError(err error, base string) error {
+	var apiErr *jira.ErrorResponse
+	switch {
+	case jira.IsUnauthorized(err):
+		return errors.New("jira rejected the credentials, run `branch jira auth init` to authenticate again")
+	case errors.As(err, &apiErr):
+		return fmt.Errorf("failed to fetch the issues of the branches: %w", err)
+	default:
+		return fmt.Errorf("failed to list branches, does base branch %s exist? %w", base, err)
+	}
+}
+
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	return s
+}
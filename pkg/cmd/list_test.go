@@ -0,0 +1,110 @@
+package cmd_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/MaikelVeen/branch/pkg/cmd"
+	"github.com/MaikelVeen/branch/pkg/git"
+	"github.com/MaikelVeen/branch/pkg/jira"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testBranches() []cmd.BranchInfo {
+	date := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	refs := []git.BranchRef{
+		{Ref: "refs/heads/main", Name: "main", CommitDate: date},
+		{Ref: "refs/heads/feature/ABC-1-login", Name: "feature/ABC-1-login", CommitDate: date},
+		{Ref: "refs/heads/ABC-2", Name: "ABC-2", CommitDate: date},
+		{Ref: "refs/heads/ABC-404", Name: "ABC-404", CommitDate: date},
+		{Ref: "refs/remotes/origin/ABC-2", Name: "origin/ABC-2", CommitDate: date},
+	}
+
+	issues := []jira.Issue{
+		{Key: "ABC-1", Fields: jira.IssueFields{
+			Summary:  "Login",
+			Status:   jira.Status{Name: "Done", StatusCategory: jira.StatusCategory{Key: jira.StatusCategoryDone, Name: "Done"}},
+			Assignee: &jira.User{DisplayName: "Jane"},
+		}},
+		{Key: "ABC-2", Fields: jira.IssueFields{
+			Status: jira.Status{Name: "In Review", StatusCategory: jira.StatusCategory{
+				Key:  jira.StatusCategoryInProgress,
+				Name: "In Progress",
+			}},
+		}},
+	}
+
+	return cmd.NewBranchInfos(refs, issues, []string{"main", "feature/ABC-1-login"}, "ABC-2")
+}
+
+func TestNewBranchInfos(t *testing.T) {
+	t.Parallel()
+
+	branches := testBranches()
+	require.Len(t, branches, 5)
+
+	assert.Empty(t, branches[0].Key)
+	assert.True(t, branches[0].Merged)
+
+	assert.Equal(t, "ABC-1", branches[1].Key)
+	assert.Equal(t, "Jane", branches[1].Assignee)
+	assert.True(t, branches[1].Done())
+	assert.True(t, branches[1].Merged)
+
+	assert.True(t, branches[2].Current)
+	assert.False(t, branches[2].Done())
+
+	// Issues that were not returned by Jira are left unannotated.
+	assert.Equal(t, "ABC-404", branches[3].Key)
+	assert.Empty(t, branches[3].Status)
+
+	assert.True(t, branches[4].Remote)
+	assert.False(t, branches[4].Current)
+	assert.Equal(t, "In Review", branches[4].Status)
+}
+
+func TestFilterBranchesByStatus(t *testing.T) {
+	t.Parallel()
+
+	names := func(branches []cmd.BranchInfo) []string {
+		var n []string
+		for _, b := range branches {
+			n = append(n, b.Branch)
+		}
+		return n
+	}
+
+	assert.Len(t, cmd.FilterBranchesByStatus(testBranches(), nil), 5)
+	assert.Equal(t, []string{"feature/ABC-1-login"}, names(cmd.FilterBranchesByStatus(testBranches(), []string{"done"})))
+	assert.Equal(t,
+		[]string{"feature/ABC-1-login", "ABC-2", "origin/ABC-2"},
+		names(cmd.FilterBranchesByStatus(testBranches(), []string{"Done", "in review"})),
+	)
+	assert.Equal(t,
+		[]string{"ABC-2", "origin/ABC-2"},
+		names(cmd.FilterBranchesByStatus(testBranches(), []string{"In Progress"})),
+		"the name of the status category",
+	)
+	assert.Equal(t,
+		[]string{"ABC-2", "origin/ABC-2"},
+		names(cmd.FilterBranchesByStatus(testBranches(), []string{jira.StatusCategoryInProgress})),
+		"the key of the status category",
+	)
+}
+
+func TestWriteBranchTable(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	require.NoError(t, cmd.WriteBranchTable(&out, testBranches()[1:3]))
+
+	date := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC).Local().Format(time.DateOnly)
+	assert.Equal(t,
+		"  BRANCH               KEY    STATUS     ASSIGNEE  LAST COMMIT  MERGED\n"+
+			"  feature/ABC-1-login  ABC-1  Done       Jane      "+date+"   yes\n"+
+			"* ABC-2                ABC-2  In Review  -         "+date+"   no\n",
+		out.String(),
+	)
+}
@@ -1,11 +1,11 @@
 package cmd
 
 import (
-	"context"
 	"errors"
 	"fmt"
 	"log/slog"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/MaikelVeen/branch/pkg/cmd/config"
@@ -19,6 +19,12 @@ import (
 	cfg "github.com/MaikelVeen/branch/pkg/config"
 )
 
+const (
+	ArgProfile = "profile"
+)
+
+var profileFlag string
+
 var rootCmd = &cobra.Command{
 	Use:   "branch",
 	Short: "branch is a VSC and Jira swiss army knife",
@@ -28,17 +34,8 @@ var rootCmd = &cobra.Command{
 			return err
 		}
 
-		authCtx, err := auth.LoadUserContext()
-		if err != nil {
-			if errors.Is(err, auth.ErrAuthContextMissing) {
-				fmt.Println("No authentication context found. Please run 'branch jira auth init' to authenticate.")
-				return nil
-			}
-			return err
-		}
-
-		ctx := context.WithValue(cmd.Context(), auth.DefaultContextKey, authCtx)
-		cmd.SetContext(ctx)
+		// The credentials are only read by the commands that use Jira.
+		cmd.SetContext(auth.WithSession(cmd.Context(), profileFlag))
 
 		return nil
 	},
@@ -56,13 +53,27 @@ func Execute() {
 
 	if err := rootCmd.Execute(); err != nil {
 		logger.Error(err.Error())
+
+		var exitErr interface{ ExitCode() int }
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.ExitCode())
+		}
 		os.Exit(1)
 	}
 }
 
 func init() {
+	rootCmd.PersistentFlags().StringVar(
+		&profileFlag,
+		ArgProfile,
+		"",
+		"Jira auth profile to use, defaults to $BRANCH_PROFILE, the jira.profile git config or the active profile",
+	)
+
 	rootCmd.AddCommand(NewCreateCommand().Command)
 	rootCmd.AddCommand(NewCopyCommand().Command)
+	rootCmd.AddCommand(NewListCommand().Command)
+	rootCmd.AddCommand(NewCleanCommand().Command)
 	rootCmd.AddCommand(jira.NewCommand().Command)
 	rootCmd.AddCommand(config.NewCommand().Command)
 }
@@ -73,10 +84,36 @@ func initializeConfig(cmd *cobra.Command) error {
 		return err
 	}
 
+	if ignored := cfg.IgnoredRepoKeys(); len(ignored) > 0 {
+		logger := slog.New(
+			tint.NewHandler(os.Stdout, &tint.Options{
+				Level:      slog.LevelInfo,
+				TimeFormat: time.Kitchen,
+			}),
+		)
+		logger.Warn(fmt.Sprintf("ignoring %s in %s, these can only be set in the user configuration",
+			strings.Join(ignored, ", "), cfg.RepoConfigFile()))
+	}
+
 	bindFlags(cmd, v)
+
+	config, err := cfg.Load()
+	if err != nil {
+		return err
+	}
+
+	store, err := auth.NewStore(config)
+	if err != nil {
+		return err
+	}
+	auth.SetStore(store)
+
 	return nil
 }
 
+// flagFromConfigAnnotation marks flags whose value is set by bindFlags.
+const flagFromConfigAnnotation = "branch_from_config"
+
 // Bind each cobra flag to its associated viper configuration (config file and environment variable).
 func bindFlags(cmd *cobra.Command, v *viper.Viper) {
 	cmd.Flags().VisitAll(func(f *pflag.Flag) {
@@ -85,6 +122,20 @@ func bindFlags(cmd *cobra.Command, v *viper.Viper) {
 		if !f.Changed && v.IsSet(configName) {
 			val := v.Get(configName)
 			_ = cmd.Flags().Set(f.Name, fmt.Sprintf("%v", val))
+			// Set marks the flag as changed, the annotation tells it was not passed.
+			_ = cmd.Flags().SetAnnotation(f.Name, flagFromConfigAnnotation, []string{"true"})
 		}
 	})
 }
+
+// flagPassed reports whether the flag `name` of `cmd` is passed on the command
+// line, rather than set from the configuration by bindFlags.
+func flagPassed(cmd *cobra.Command, name string) bool {
+	f := cmd.Flags().Lookup(name)
+	if f == nil || !f.Changed {
+		return false
+	}
+
+	_, fromConfig := f.Annotations[flagFromConfigAnnotation]
+	return !fromConfig
+}
@@ -0,0 +1,312 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/MaikelVeen/branch/pkg/git"
+	"github.com/MaikelVeen/branch/pkg/jira"
+
+	cfg "github.com/MaikelVeen/branch/pkg/config"
+)
+
+// Names of the values available in branch name templates.
+const (
+	TemplateKey         = "key"
+	TemplateType        = "type"
+	TemplateSummary     = "summary"
+	TemplateTitle       = "title"
+	TemplateProject     = "project"
+	TemplateParent      = "parent"
+	TemplateEpic        = "epic"
+	TemplatePriority    = "priority"
+	TemplateComponents  = "components"
+	TemplateLabels      = "labels"
+	TemplateFixVersions = "fixVersions"
+	TemplateAssignee    = "assignee"
+	TemplateReporter    = "reporter"
+	TemplateSprint      = "sprint"
+	TemplateCustom      = "custom"
+	TemplatePrefix      = "prefix"
+)
+
+func init() {
+	cfg.AddValidator(cfg.KeyTemplate, ValidateTemplate)
+}
+
+// ValidateTemplate returns an error when `tmpl` can not be parsed, or uses values or
+// functions that do not exist or with the wrong arguments.
+func ValidateTemplate(tmpl string) error {
+	if strings.TrimSpace(tmpl) == "" {
+		return errors.New("the template is empty")
+	}
+
+	issue := &jira.Issue{
+		Key: "ABC-1",
+		Fields: jira.IssueFields{
+			Summary:   "Validate the template",
+			Issuetype: jira.IssueType{Name: "Story"},
+		},
+	}
+
+	_, err := BranchNameFromTemplate(tmpl, issue)
+	return err
+}
+
+// TemplateData is the data of a branch name template. Lists, such as labels, are
+// []string values, custom fields are strings or []string values by field name and
+// all other values are strings, empty when the issue does not have them.
+type TemplateData map[string]any
+
+// NewTemplateData returns the template data of `issue`, where `summary` is the
+// sanitized summary and the prefix is looked up in `prefixes`.
+func NewTemplateData(issue *jira.Issue, summary string, prefixes cfg.PrefixConfig) TemplateData {
+	f := issue.Fields
+
+	var parent, priority string
+	if f.Parent != nil {
+		parent = f.Parent.Key
+	}
+	if f.Priority != nil {
+		priority = f.Priority.Name
+	}
+
+	components := make([]string, 0, len(f.Components))
+	for _, c := range f.Components {
+		components = append(components, c.Name)
+	}
+
+	fixVersions := make([]string, 0, len(f.FixVersions))
+	for _, v := range f.FixVersions {
+		fixVersions = append(fixVersions, v.Name)
+	}
+
+	return TemplateData{
+		TemplateKey:         issue.Key,
+		TemplateType:        strings.ToLower(f.Issuetype.Name),
+		TemplateSummary:     summary,
+		TemplateTitle:       f.Summary,
+		TemplateProject:     projectKey(issue),
+		TemplateParent:      parent,
+		TemplateEpic:        issue.EpicKey(),
+		TemplatePriority:    priority,
+		TemplateComponents:  components,
+		TemplateLabels:      append([]string{}, f.Labels...),
+		TemplateFixVersions: fixVersions,
+		TemplateAssignee:    userName(f.Assignee),
+		TemplateReporter:    userName(f.Reporter),
+		TemplateSprint:      issue.Sprint(),
+		TemplateCustom:      issue.CustomFields(),
+		TemplatePrefix:      prefixes.For(f.Issuetype.Name, f.Labels),
+	}
+}
+
+// addMissingCustomFields adds the custom fields that `t` refers to, as .custom.Name
+// or index .custom "Name", to `data` as empty strings when the issue does not have
+// them. Like other values the issue does not have, they are empty.
+func addMissingCustomFields(t *template.Template, data TemplateData) {
+	custom, ok := data[TemplateCustom].(map[string]any)
+	if !ok {
+		return
+	}
+
+	for _, tmpl := range t.Templates() {
+		if tmpl.Tree == nil {
+			continue
+		}
+
+		for _, name := range customFieldNames(tmpl.Tree.Root) {
+			if _, ok := custom[name]; !ok {
+				custom[name] = ""
+			}
+		}
+	}
+}
+
+// customFieldNames returns the names of the custom fields referred to in `node`.
+func customFieldNames(node parse.Node) []string {
+	var names []string
+
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			names = append(names, customFieldNames(child)...)
+		}
+	case *parse.ActionNode:
+		names = customFieldNames(n.Pipe)
+	case *parse.IfNode:
+		names = branchCustomFieldNames(&n.BranchNode)
+	case *parse.RangeNode:
+		names = branchCustomFieldNames(&n.BranchNode)
+	case *parse.WithNode:
+		names = branchCustomFieldNames(&n.BranchNode)
+	case *parse.TemplateNode:
+		names = customFieldNames(n.Pipe)
+	case *parse.PipeNode:
+		if n == nil {
+			return nil
+		}
+		for _, cmd := range n.Cmds {
+			names = append(names, customFieldNames(cmd)...)
+		}
+	case *parse.CommandNode:
+		if name, ok := indexedCustomField(n.Args); ok {
+			names = append(names, name)
+		}
+		for _, arg := range n.Args {
+			names = append(names, customFieldNames(arg)...)
+		}
+	case *parse.ChainNode:
+		names = customFieldNames(n.Node)
+	case *parse.FieldNode:
+		if len(n.Ident) > 1 && n.Ident[0] == TemplateCustom {
+			names = append(names, n.Ident[1])
+		}
+	case *parse.VariableNode:
+		if len(n.Ident) > 2 && n.Ident[0] == "$" && n.Ident[1] == TemplateCustom {
+			names = append(names, n.Ident[2])
+		}
+	}
+
+	return names
+}
+
+func branchCustomFieldNames(n *parse.BranchNode) []string {
+	names := customFieldNames(n.Pipe)
+	names = append(names, customFieldNames(n.List)...)
+	return append(names, customFieldNames(n.ElseList)...)
+}
+
+// indexedCustomField returns the name of the custom field in `index .custom "Name"`.
+func indexedCustomField(args []parse.Node) (string, bool) {
+	if len(args) < 3 {
+		return "", false
+	}
+
+	fn, ok := args[0].(*parse.IdentifierNode)
+	if !ok || fn.Ident != "index" {
+		return "", false
+	}
+
+	field, ok := args[1].(*parse.FieldNode)
+	if !ok || len(field.Ident) != 1 || field.Ident[0] != TemplateCustom {
+		return "", false
+	}
+
+	name, ok := args[2].(*parse.StringNode)
+	if !ok {
+		return "", false
+	}
+
+	return name.Text, true
+}
+
+// userName returns the display name of `u`, falling back to the username.
+func userName(u *jira.User) string {
+	switch {
+	case u == nil:
+		return ""
+	case u.DisplayName != "":
+		return u.DisplayName
+	default:
+		return u.Name
+	}
+}
+
+// TemplateFuncs returns the functions available in branch name templates. The
+// slug function sanitizes text with `sanitize`, mapType looks up the prefix of
+// an issue type in `prefixes`.
+func TemplateFuncs(sanitize git.SanitizeOptions, prefixes cfg.PrefixConfig) template.FuncMap {
+	return template.FuncMap{
+		"mapType": func(issueType any) string { return prefixes.ForType(templateString(issueType)) },
+		// slug sanitizes text like the summary, optionally limited to a number of words.
+		"slug": func(s any, maxWords ...int) (string, error) {
+			opts := sanitize
+			if len(maxWords) > 0 {
+				opts.MaxWords = maxWords[0]
+			}
+			opts.Fallback = ""
+
+			return git.Sanitize(templateString(s), opts)
+		},
+		// truncate cuts text to at most n characters, without dangling separators.
+		"truncate": func(n int, s any) string {
+			r := []rune(templateString(s))
+			if n < 0 || len(r) <= n {
+				return string(r)
+			}
+
+			return strings.TrimRight(string(r[:n]), "-_./ ")
+		},
+		"lower": func(s any) string { return strings.ToLower(templateString(s)) },
+		"upper": func(s any) string { return strings.ToUpper(templateString(s)) },
+		// replace replaces all occurrences of from by to.
+		"replace": func(from, to string, s any) string {
+			return strings.ReplaceAll(templateString(s), from, to)
+		},
+		// default returns def when the value is empty.
+		"default": func(def, value any) any {
+			if isEmptyValue(value) {
+				return def
+			}
+
+			return value
+		},
+		// first returns the first element of a list, or an empty string.
+		"first": func(list any) (any, error) {
+			v := reflect.ValueOf(list)
+			if !v.IsValid() {
+				return "", nil
+			}
+
+			switch v.Kind() {
+			case reflect.Slice, reflect.Array:
+				if v.Len() == 0 {
+					return "", nil
+				}
+				return v.Index(0).Interface(), nil
+			case reflect.String:
+				return list, nil
+			default:
+				return nil, fmt.Errorf("first: can not take the first element of %T", list)
+			}
+		},
+	}
+}
+
+// templateString formats a template value as a string, lists are joined with spaces.
+func templateString(v any) string {
+	switch s := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return s
+	case []string:
+		return strings.Join(s, " ")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func isEmptyValue(v any) bool {
+	r := reflect.ValueOf(v)
+	if !r.IsValid() {
+		return true
+	}
+
+	switch r.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return r.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return r.IsNil()
+	default:
+		return r.IsZero()
+	}
+}
@@ -0,0 +1,212 @@
+package cmd_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/MaikelVeen/branch/pkg/cmd"
+	"github.com/MaikelVeen/branch/pkg/config"
+	"github.com/MaikelVeen/branch/pkg/jira"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func templateIssue() *jira.Issue {
+	return &jira.Issue{
+		Key: "ABC-1",
+		Fields: jira.IssueFields{
+			Summary:     "Fix the login page on mobile devices",
+			Issuetype:   jira.IssueType{Name: "Story"},
+			Project:     jira.Project{Key: "ABC"},
+			Parent:      &jira.Parent{Key: "ABC-100", Fields: jira.ParentFields{Issuetype: jira.IssueType{Name: "Epic"}}},
+			Priority:    &jira.Priority{Name: "High"},
+			Components:  []jira.Component{{Name: "Backend"}, {Name: "API"}},
+			Labels:      []string{"security", "mobile"},
+			FixVersions: []jira.Version{{Name: "1.2.0"}},
+			Assignee:    &jira.User{DisplayName: "Jane Doe"},
+			Reporter:    &jira.User{Name: "jsmith"},
+			Custom: map[string]json.RawMessage{
+				"customfield_10020": json.RawMessage(`[{"name":"Sprint 7","state":"active"}]`),
+				"customfield_10030": json.RawMessage(`{"value":"Payments"}`),
+			},
+		},
+		Names: map[string]string{"customfield_10020": "Sprint", "customfield_10030": "Team"},
+	}
+}
+
+func TestNewTemplateData(t *testing.T) {
+	t.Parallel()
+
+	data := cmd.NewTemplateData(templateIssue(), "fix-the-login", config.PrefixConfig{})
+
+	assert.Equal(t, cmd.TemplateData{
+		"key":         "ABC-1",
+		"type":        "story",
+		"summary":     "fix-the-login",
+		"title":       "Fix the login page on mobile devices",
+		"project":     "ABC",
+		"parent":      "ABC-100",
+		"epic":        "ABC-100",
+		"priority":    "High",
+		"components":  []string{"Backend", "API"},
+		"labels":      []string{"security", "mobile"},
+		"fixVersions": []string{"1.2.0"},
+		"assignee":    "Jane Doe",
+		"reporter":    "jsmith",
+		"sprint":      "Sprint 7",
+		"custom": map[string]any{
+			"Sprint": []string{"Sprint 7"},
+			"Team":   "Payments",
+		},
+		"prefix": "feature",
+	}, data)
+}
+
+func TestBranchNameFromTemplateFuncs(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		template string
+		want     string
+	}{
+		"project and slug with a word limit": {
+			template: "{{.type}}/{{.project}}/{{.key}}-{{slug .title 3}}",
+			want:     "story/ABC/ABC-1-fix-the-login",
+		},
+		"truncate without dangling separators": {
+			template: "{{.key}}-{{.summary | truncate 10}}",
+			want:     "ABC-1-fix-the-lo",
+		},
+		"lower and upper": {
+			template: "{{.project | lower}}/{{.type | upper}}",
+			want:     "abc/STORY",
+		},
+		"replace": {
+			template: `{{.assignee | replace " " "." | lower}}/{{.key}}`,
+			want:     "jane.doe/ABC-1",
+		},
+		"first of a list": {
+			template: "{{first .components | lower}}/{{.key}}",
+			want:     "backend/ABC-1",
+		},
+		"default for an empty value": {
+			template: `{{.key}}-{{first .fixVersions | default "next"}}-{{.sprint | slug}}`,
+			want:     "ABC-1-1.2.0-sprint-7",
+		},
+		"custom field by name": {
+			template: `{{index .custom "Team" | slug}}/{{.key}}`,
+			want:     "payments/ABC-1",
+		},
+		"prefix of the issue type": {
+			template: "{{.prefix}}/{{.key}}",
+			want:     "feature/ABC-1",
+		},
+		"mapType": {
+			template: "{{.type | mapType}}/{{.key}}",
+			want:     "feature/ABC-1",
+		},
+		"default for a missing custom field": {
+			template: `{{index .custom "Squad" | default "core"}}/{{.key}}`,
+			want:     "core/ABC-1",
+		},
+		"missing custom field": {
+			template: "{{.key}}-{{.custom.Squad}}",
+			want:     "ABC-1",
+		},
+		"index of a missing custom field": {
+			template: `{{.key}}-{{index .custom "Squad"}}`,
+			want:     "ABC-1",
+		},
+		"default for a missing custom field by name": {
+			template: `{{default "core" .custom.Squad}}/{{.key}}`,
+			want:     "core/ABC-1",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := cmd.BranchNameFromTemplate(tc.template, templateIssue())
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestBranchNameFromTemplateWithPrefixes(t *testing.T) {
+	t.Parallel()
+
+	fallback := "misc"
+	prefixes := config.PrefixConfig{
+		Types:    map[string]string{"story": "feat"},
+		Labels:   map[string]string{"mobile": "app"},
+		Fallback: &fallback,
+	}
+
+	issue := templateIssue()
+	got, err := cmd.BranchNameFromTemplate("{{.prefix}}/{{.type | mapType}}/{{.key}}", issue, cmd.WithPrefixes(prefixes))
+	require.NoError(t, err)
+	assert.Equal(t, "app/feat/ABC-1", got)
+
+	issue.Fields.Labels = nil
+	issue.Fields.Issuetype.Name = "Spike"
+	got, err = cmd.BranchNameFromTemplate("{{.prefix}}/{{.key}}", issue, cmd.WithPrefixes(prefixes))
+	require.NoError(t, err)
+	assert.Equal(t, "misc/ABC-1", got)
+}
+
+func TestBranchNameFromTemplateUnknownValue(t *testing.T) {
+	t.Parallel()
+
+	_, err := cmd.BranchNameFromTemplate("{{.typo}}/{{.key}}", templateIssue())
+	require.ErrorContains(t, err, `map has no entry for key "typo"`)
+}
+
+func TestBranchNameFromTemplateEmptyList(t *testing.T) {
+	t.Parallel()
+
+	issue := templateIssue()
+	issue.Fields.Labels = nil
+
+	got, err := cmd.BranchNameFromTemplate(`{{first .labels | default "misc"}}/{{.key}}`, issue)
+	require.NoError(t, err)
+	assert.Equal(t, "misc/ABC-1", got)
+}
+
+func TestBranchNameFromTemplateEmptyValues(t *testing.T) {
+	t.Parallel()
+
+	issue := templateIssue()
+	issue.Fields.Parent = nil
+	issue.Fields.Labels = nil
+	issue.Fields.Custom = nil
+
+	got, err := cmd.BranchNameFromTemplate("{{.parent}}/{{.key}}-{{first .labels}}-{{.sprint}}", issue)
+	require.NoError(t, err)
+	assert.Equal(t, "ABC-1", got)
+}
+
+func TestValidateTemplate(t *testing.T) {
+	t.Parallel()
+
+	for _, tmpl := range []string{
+		config.DefaultTemplate,
+		"{{.prefix}}/{{.project}}/{{.key}}-{{slug .title 5}}",
+		`{{first .labels | default "misc"}}/{{.key}}`,
+		"{{.custom.Team}}/{{.key}}",
+		`{{default "x" .custom.Team}}/{{.key}}`,
+		`{{index .custom "Team"}}/{{.key}}`,
+	} {
+		require.NoError(t, cmd.ValidateTemplate(tmpl), tmpl)
+	}
+
+	for _, tmpl := range []string{"", "{{.key", "{{.typo}}", "{{slug}}", "{{unknown .key}}"} {
+		require.Error(t, cmd.ValidateTemplate(tmpl), tmpl)
+	}
+
+	// The template option is validated when it is set.
+	opt, ok := config.LookupOption(config.KeyTemplate)
+	require.True(t, ok)
+	require.ErrorContains(t, opt.Check("{{.typo}}"), "invalid value for template")
+}
@@ -5,17 +5,81 @@ import (
 	"fmt"
 	"os"
 	"os/user"
+	"path/filepath"
 	"strings"
 
 	"github.com/spf13/viper"
+
+	"github.com/MaikelVeen/branch/pkg/git"
 )
 
 var (
+	// configuration holds the merged configuration layers, it is used to read values.
 	configuration *viper.Viper
+	// userConfiguration holds the user configuration file, it is used to write values.
+	userConfiguration *viper.Viper
 )
 
 const (
-	KeyTemplate = "template"
+	KeyTemplate         = "template"
+	KeyBase             = "base"
+	KeyBaseRules        = "base-rules"
+	KeyCreateTransition = "create.transition"
+	KeyCreateAssign     = "create.assign"
+	KeyCreateComment    = "create.comment"
+	KeyCreateFrom       = "create.from"
+	KeyCreateDirty      = "create.dirty"
+	KeyPickerJQL        = "picker.jql"
+	KeyCleanProtected   = "clean.protected"
+	KeyPrefixFallback   = "prefix.fallback"
+
+	KeySanitizeSeparator    = "sanitize.separator"
+	KeySanitizeCase         = "sanitize.case"
+	KeySanitizeMaxWords     = "sanitize.max-words"
+	KeySanitizeMaxLength    = "sanitize.max-length"
+	KeySanitizeStopWords    = "sanitize.stop-words"
+	KeySanitizeKeepBrackets = "sanitize.keep-brackets"
+	KeySanitizeFallback     = "sanitize.fallback"
+	KeySanitizeRepair       = "sanitize.repair"
+	KeyCredentialStore      = "credentials.store"
+	KeyCredentialHelper     = "credentials.helper"
+
+	// CredentialStoreKeyring stores credentials in the keyring of the operating system.
+	CredentialStoreKeyring = "keyring"
+	// CredentialStoreFile stores credentials in a passphrase encrypted file.
+	CredentialStoreFile = "file"
+	// CredentialStoreEnv reads credentials from environment variables.
+	CredentialStoreEnv = "env"
+	// CredentialStoreHelper delegates to an external credential helper.
+	CredentialStoreHelper = "helper"
+
+	// CreateFromLocal creates branches from the local base branch, which is checked out first.
+	CreateFromLocal = "local"
+	// CreateFromRemote creates branches from the base branch on the remote, which is fetched first.
+	CreateFromRemote = "remote"
+
+	// DirtyAbort refuses to create a branch when the working tree has changes.
+	DirtyAbort = "abort"
+	// DirtyCarry carries the changes in the working tree over to the new branch.
+	DirtyCarry = "carry"
+	// DirtyStash stashes the changes in the working tree and applies them to the new branch.
+	DirtyStash = "stash"
+
+	// DefaultTemplate is the template of branch names.
+	DefaultTemplate = "{{.type}}/{{.key}}-{{.summary}}"
+	// DefaultBaseBranch is the branch new branches are based on when no base branch
+	// is configured and the default branch of the remote can not be determined.
+	DefaultBaseBranch = "main"
+
+	// DefaultPickerJQL selects the issues offered by `branch create` without a key.
+	DefaultPickerJQL = "assignee = currentUser() AND resolution = Unresolved AND sprint in openSprints() ORDER BY updated DESC"
+
+	// projectsKey is the key under which project specific configuration is stored.
+	projectsKey = "projects"
+	// prefixTypesKey and prefixLabelsKey are the keys under which the branch
+	// prefixes of issue types and labels are stored.
+	prefixTypesKey  = "prefix.types"
+	prefixLabelsKey = "prefix.labels"
 
 	defaultConfigFilename = "config"
 	path                  = "$HOME/.config/branch/"
@@ -24,19 +88,288 @@ const (
 
 // Config represents the configuration of the application.
 type Config struct {
-	Template *string `yaml:"template"`
+	Template    *string `yaml:"template"`
+	Base        *string
+	BaseRules   map[string]string `mapstructure:"base-rules"`
+	Create      CreateConfig
+	Projects    map[string]*ProjectConfig
+	Credentials CredentialsConfig
+	Picker      PickerConfig
+	Clean       CleanConfig
+	Sanitize    SanitizeConfig
+	Prefix      PrefixConfig
 }
 
-func (c *Config) Save() error {
-	return configuration.WriteConfig()
+// BaseBranch returns the configured base branch, or an empty string when the
+// default branch of the remote is used.
+func (c *Config) BaseBranch() string {
+	if c.Base == nil {
+		return ""
+	}
+
+	return *c.Base
+}
+
+// BaseFor returns the base branch rule of an issue with `issueType` whose branch
+// has `prefix`. Rules are keyed by lower cased issue type or branch prefix, the
+// issue type is looked up first. The base branch can be a glob pattern.
+func (c *Config) BaseFor(issueType, prefix string) (string, bool) {
+	for _, key := range []string{issueType, prefix} {
+		key = strings.ToLower(strings.TrimSpace(key))
+		if base, ok := c.BaseRules[key]; ok && key != "" && base != "" {
+			return base, true
+		}
+	}
+
+	return "", false
+}
+
+// DefaultTypePrefixes maps lower cased issue types to branch prefixes. Configured
+// type prefixes are added to these and take precedence.
+var DefaultTypePrefixes = map[string]string{
+	"story":       "feature",
+	"new feature": "feature",
+	"improvement": "feature",
+	"epic":        "feature",
+	"bug":         "bugfix",
+	"task":        "chore",
+	"sub-task":    "chore",
+	"subtask":     "chore",
+}
+
+// PrefixConfig maps issues to the prefix of their branch, available in the
+// branch template as {{.prefix}}.
+type PrefixConfig struct {
+	// Types maps lower cased issue types to prefixes.
+	Types map[string]string
+	// Labels maps lower cased labels to prefixes, these take precedence over types.
+	Labels map[string]string
+	// Fallback is the prefix of unmapped issue types. Defaults to the sanitized type.
+	Fallback *string
+}
+
+// For returns the prefix of an issue with `issueType` and `labels`. The first label
+// with a prefix wins, otherwise the prefix of the issue type is returned.
+func (p PrefixConfig) For(issueType string, labels []string) string {
+	for _, label := range labels {
+		if prefix, ok := p.Labels[strings.ToLower(label)]; ok {
+			return prefix
+		}
+	}
+
+	return p.ForType(issueType)
+}
+
+// ForType returns the prefix of `issueType`, from the configured types, the
+// DefaultTypePrefixes or the fallback, in that order.
+func (p PrefixConfig) ForType(issueType string) string {
+	t := strings.ToLower(strings.TrimSpace(issueType))
+	if prefix, ok := p.Types[t]; ok {
+		return prefix
+	}
+
+	if prefix, ok := DefaultTypePrefixes[t]; ok {
+		return prefix
+	}
+
+	if p.Fallback != nil {
+		return *p.Fallback
+	}
+
+	return git.FormatAsValidRef(t)
+}
+
+// SanitizeConfig controls how the issue summary is turned into a part of the
+// branch name. Unset values default to git.DefaultSanitizeOptions.
+type SanitizeConfig struct {
+	Separator    *string
+	Case         *string
+	MaxWords     *int     `mapstructure:"max-words"`
+	MaxLength    *int     `mapstructure:"max-length"`
+	StopWords    []string `mapstructure:"stop-words"`
+	KeepBrackets *bool    `mapstructure:"keep-brackets"`
+	// Replacements are regular expression replacements applied to the summary
+	// first. They can only be set in the configuration file.
+	Replacements []ReplacementConfig
+	// Fallback is used when nothing is left of the summary.
+	Fallback *string
+	// Repair repairs branch names that git does not accept instead of failing.
+	Repair *bool
+}
+
+// RepairBranchNames reports whether invalid branch names are repaired.
+func (c *Config) RepairBranchNames() bool {
+	return c.Sanitize.Repair != nil && *c.Sanitize.Repair
 }
 
-// Option represents a configuration option that can be displayed to the user.
-type Option struct {
-	Key          string
-	Description  string
-	CurrentValue func(cfg Config) *string
-	SetValue     func(cfg *Config, value string) error
+// ReplacementConfig replaces matches of the regular expression Pattern with Replace.
+type ReplacementConfig struct {
+	Pattern string
+	Replace string
+}
+
+// SanitizeOptions returns the sanitize options with the configured values applied
+// to the defaults. Returns an error when the configured values are invalid.
+func (c *Config) SanitizeOptions() (git.SanitizeOptions, error) {
+	opts := git.DefaultSanitizeOptions()
+	s := c.Sanitize
+
+	if s.Separator != nil {
+		opts.Separator = *s.Separator
+	}
+	if s.Case != nil {
+		opts.Case = git.CaseMode(strings.ToLower(*s.Case))
+	}
+	if s.MaxWords != nil {
+		opts.MaxWords = *s.MaxWords
+	}
+	if s.MaxLength != nil {
+		opts.MaxLength = *s.MaxLength
+	}
+	if s.KeepBrackets != nil {
+		opts.KeepBrackets = *s.KeepBrackets
+	}
+	if s.Fallback != nil {
+		opts.Fallback = *s.Fallback
+	}
+	opts.StopWords = s.StopWords
+	for _, r := range s.Replacements {
+		opts.Replacements = append(opts.Replacements, git.Replacement{Pattern: r.Pattern, Replace: r.Replace})
+	}
+
+	if err := opts.Validate(); err != nil {
+		return opts, fmt.Errorf("invalid sanitize configuration: %w", err)
+	}
+
+	return opts, nil
+}
+
+// DefaultProtectedBranches are never deleted by `branch clean` unless configured otherwise.
+var DefaultProtectedBranches = []string{"main", "master", "develop"}
+
+// CleanConfig configures `branch clean`.
+type CleanConfig struct {
+	// Protected lists branch names or glob patterns that are never deleted.
+	Protected []string
+}
+
+// ProtectedBranches returns the configured protected branches, defaulting to DefaultProtectedBranches.
+func (c *Config) ProtectedBranches() []string {
+	if c.Clean.Protected == nil {
+		return DefaultProtectedBranches
+	}
+
+	return c.Clean.Protected
+}
+
+// PickerConfig configures the issue picker of `branch create`.
+type PickerConfig struct {
+	// JQL selects the issues to pick from.
+	JQL *string
+}
+
+// PickerJQL returns the configured picker query, defaulting to DefaultPickerJQL.
+func (c *Config) PickerJQL() string {
+	if c.Picker.JQL == nil || strings.TrimSpace(*c.Picker.JQL) == "" {
+		return DefaultPickerJQL
+	}
+
+	return *c.Picker.JQL
+}
+
+// CredentialsConfig selects where the Jira credentials are stored.
+type CredentialsConfig struct {
+	// Store is one of keyring, file, env or helper. Defaults to keyring.
+	Store *string
+	// Helper is the credential helper used by the helper store.
+	Helper *string
+}
+
+// CredentialStore returns the configured credential store, defaulting to the keyring.
+func (c *Config) CredentialStore() string {
+	if c.Credentials.Store == nil || *c.Credentials.Store == "" {
+		return CredentialStoreKeyring
+	}
+
+	return *c.Credentials.Store
+}
+
+// CreateConfig configures `branch create`. Except for From and Dirty, it holds the
+// actions that are performed on the issue after the new branch is checked out.
+type CreateConfig struct {
+	// From is where new branches are created from, CreateFromLocal or CreateFromRemote.
+	// It can not be set per project.
+	From *string
+	// Dirty is what happens to changes in the working tree, DirtyAbort, DirtyCarry
+	// or DirtyStash. It can not be set per project.
+	Dirty *string
+	// Transition is the name of the transition or status to move the issue to.
+	Transition *string
+	// Assign assigns the issue to the authenticated user.
+	Assign *bool
+	// Comment adds a comment with the name of the new branch to the issue.
+	Comment *bool
+}
+
+// CreateFromRemote reports whether new branches are created from the base branch
+// on the remote instead of the local base branch.
+func (c *Config) CreateFromRemote() bool {
+	return c.Create.From != nil && strings.EqualFold(*c.Create.From, CreateFromRemote)
+}
+
+// DirtyMode returns what happens to changes in the working tree when a branch is
+// created, defaulting to DirtyAbort.
+func (c *Config) DirtyMode() string {
+	if c.Create.Dirty == nil || *c.Create.Dirty == "" {
+		return DirtyAbort
+	}
+
+	return strings.ToLower(*c.Create.Dirty)
+}
+
+// ValidateDirtyMode returns an error when `mode` is not one of DirtyAbort, DirtyCarry or DirtyStash.
+func ValidateDirtyMode(mode string) error {
+	switch strings.ToLower(mode) {
+	case DirtyAbort, DirtyCarry, DirtyStash:
+		return nil
+	default:
+		return fmt.Errorf("invalid mode %q, use %s, %s or %s", mode, DirtyAbort, DirtyCarry, DirtyStash)
+	}
+}
+
+// ProjectConfig holds configuration that only applies to issues of one project.
+type ProjectConfig struct {
+	Create CreateConfig
+}
+
+// CreateFor returns the create configuration for issues in `project`,
+// where project specific values take precedence over the global ones.
+func (c *Config) CreateFor(project string) CreateConfig {
+	create := c.Create
+
+	// Keys are case insensitive, viper stores them in lower case.
+	p, ok := c.Projects[strings.ToLower(project)]
+	if !ok || p == nil {
+		return create
+	}
+
+	if p.Create.Transition != nil {
+		create.Transition = p.Create.Transition
+	}
+	if p.Create.Assign != nil {
+		create.Assign = p.Create.Assign
+	}
+	if p.Create.Comment != nil {
+		create.Comment = p.Create.Comment
+	}
+
+	return create
+}
+
+// Save writes the values set through the options to the user configuration file.
+// Values from the repository configuration are never written to it.
+func (c *Config) Save() error {
+	return userConfiguration.WriteConfig()
 }
 
 // Load loads the configuration from the environment.
@@ -53,28 +386,18 @@ func Load() (*Config, error) {
 	return &cfg, nil
 }
 
-// Options is a list of all available configuration options.
-var Options = map[string]*Option{
-	KeyTemplate: {
-		Key:          KeyTemplate,
-		Description:  "Template to use for branch name",
-		CurrentValue: func(cfg Config) *string { return cfg.Template },
-		SetValue: func(cfg *Config, value string) error {
-			cfg.Template = &value
-			configuration.Set(KeyTemplate, value)
-			return nil
-		},
-	},
-}
-
+// Init reads the user configuration file and the repository configuration file
+// of the working directory, and returns the merged configuration. Values are taken
+// from the environment, the repository file and the user file, in that order. The
+// credentials can not be set in the repository file, see IgnoredRepoKeys.
 func Init() (*viper.Viper, error) {
-	v := viper.New()
+	u := viper.New()
 
-	v.SetConfigName(defaultConfigFilename)
-	v.SetConfigType("yaml")
-	v.AddConfigPath(path)
+	u.SetConfigName(defaultConfigFilename)
+	u.SetConfigType("yaml")
+	u.AddConfigPath(path)
 
-	if err := v.ReadInConfig(); err != nil {
+	if err := u.ReadInConfig(); err != nil {
 		var e viper.ConfigFileNotFoundError
 		if errors.As(err, &e) {
 			// If the configuration file is not found, create it.
@@ -86,21 +409,57 @@ func Init() (*viper.Viper, error) {
 		}
 	}
 
+	v := viper.New()
+	if err := v.MergeConfigMap(u.AllSettings()); err != nil {
+		return nil, fmt.Errorf("failed to read configuration: %w", err)
+	}
+
+	var r *viper.Viper
+	var ignored []string
+	if wd, err := os.Getwd(); err == nil {
+		if file, ok := FindRepoConfig(wd); ok {
+			if r, ignored, err = readRepoConfig(file); err != nil {
+				return nil, err
+			}
+
+			if err = v.MergeConfigMap(r.AllSettings()); err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", file, err)
+			}
+		}
+	}
+
 	v.SetEnvPrefix(envPrefix)
-	v.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
+	v.SetEnvKeyReplacer(envKeyReplacer)
 	v.AutomaticEnv()
 
+	// Without binding, environment variables are only used for keys in a file.
+	for key := range Options {
+		_ = v.BindEnv(key)
+	}
+
 	configuration = v
+	userConfiguration = u
+	repoConfiguration = r
+	ignoredRepoKeys = ignored
 	return v, nil
 }
 
-func createDefaultConfigFile() error {
+// Dir returns the directory that holds the user configuration.
+func Dir() (string, error) {
 	usr, err := user.Current()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(usr.HomeDir, ".config", "branch"), nil
+}
+
+func createDefaultConfigFile() error {
+	path, err := Dir()
 	if err != nil {
 		return err
 	}
 
-	path := fmt.Sprintf("%s/.config/branch", usr.HomeDir)
 	if err = os.MkdirAll(path, 0755); err != nil {
 		return err
 	}
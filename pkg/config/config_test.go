@@ -0,0 +1,199 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/MaikelVeen/branch/pkg/config"
+	"github.com/MaikelVeen/branch/pkg/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateFor(t *testing.T) {
+	t.Parallel()
+
+	transition := "In Progress"
+	review := "In Review"
+	yes, no := true, false
+
+	cfg := config.Config{
+		Create: config.CreateConfig{Transition: &transition, Assign: &yes},
+		Projects: map[string]*config.ProjectConfig{
+			"ops": {Create: config.CreateConfig{Transition: &review, Assign: &no}},
+		},
+	}
+
+	global := cfg.CreateFor("PROJ")
+	assert.Equal(t, &transition, global.Transition)
+	assert.Equal(t, &yes, global.Assign)
+	assert.Nil(t, global.Comment)
+
+	project := cfg.CreateFor("OPS")
+	assert.Equal(t, &review, project.Transition)
+	assert.Equal(t, &no, project.Assign)
+	assert.Nil(t, project.Comment)
+}
+
+func TestLookupOption(t *testing.T) {
+	t.Parallel()
+
+	for _, key := range []string{
+		config.KeyTemplate,
+		config.KeyCreateTransition,
+		config.KeyCreateAssign,
+		config.KeyCreateComment,
+		"projects.PROJ.create.transition",
+		"projects.proj.create.assign",
+		config.KeyPrefixFallback,
+		"prefix.types.story",
+		"prefix.labels.Hotfix",
+	} {
+		_, ok := config.LookupOption(key)
+		assert.True(t, ok, key)
+	}
+
+	for _, key := range []string{"unknown", "projects.PROJ", "projects..create.assign", "projects.PROJ.template", "prefix.types."} {
+		_, ok := config.LookupOption(key)
+		assert.False(t, ok, key)
+	}
+
+	yes := true
+	opt, ok := config.LookupOption("projects.PROJ.create.assign")
+	require.True(t, ok)
+	assert.Equal(t, "true", *opt.CurrentValue(config.Config{
+		Projects: map[string]*config.ProjectConfig{"proj": {Create: config.CreateConfig{Assign: &yes}}},
+	}))
+}
+
+func TestPickerJQL(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.Config{}
+	assert.Equal(t, config.DefaultPickerJQL, cfg.PickerJQL())
+
+	blank := " "
+	cfg.Picker.JQL = &blank
+	assert.Equal(t, config.DefaultPickerJQL, cfg.PickerJQL())
+
+	jql := "project = TEST"
+	cfg.Picker.JQL = &jql
+	assert.Equal(t, jql, cfg.PickerJQL())
+}
+
+func TestProtectedBranches(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.Config{}
+	assert.Equal(t, config.DefaultProtectedBranches, cfg.ProtectedBranches())
+
+	cfg.Clean.Protected = []string{}
+	assert.Empty(t, cfg.ProtectedBranches())
+
+	cfg.Clean.Protected = []string{"trunk", "release/*"}
+	assert.Equal(t, []string{"trunk", "release/*"}, cfg.ProtectedBranches())
+}
+
+func TestSanitizeOptions(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.Config{}
+	opts, err := cfg.SanitizeOptions()
+	require.NoError(t, err)
+	assert.Equal(t, git.DefaultSanitizeOptions(), opts)
+
+	sep, upper, words := "_", "UPPER", 5
+	cfg.Sanitize = config.SanitizeConfig{
+		Separator:    &sep,
+		Case:         &upper,
+		MaxWords:     &words,
+		StopWords:    []string{"the"},
+		Replacements: []config.ReplacementConfig{{Pattern: "&", Replace: "and"}},
+	}
+
+	opts, err = cfg.SanitizeOptions()
+	require.NoError(t, err)
+	assert.Equal(t, "_", opts.Separator)
+	assert.Equal(t, git.CaseUpper, opts.Case)
+	assert.Equal(t, 5, opts.MaxWords)
+	assert.Equal(t, []string{"the"}, opts.StopWords)
+	assert.Equal(t, []git.Replacement{{Pattern: "&", Replace: "and"}}, opts.Replacements)
+
+	invalid := "~"
+	cfg.Sanitize.Separator = &invalid
+	_, err = cfg.SanitizeOptions()
+	require.Error(t, err)
+}
+
+func TestPrefixFor(t *testing.T) {
+	t.Parallel()
+
+	var prefixes config.PrefixConfig
+	assert.Equal(t, "feature", prefixes.ForType("Story"))
+	assert.Equal(t, "bugfix", prefixes.ForType("Bug"))
+	assert.Equal(t, "chore", prefixes.ForType("Sub-task"))
+	assert.Equal(t, "technical-debt", prefixes.ForType("Technical Debt"), "unmapped types are sanitized")
+
+	fallback := "misc"
+	prefixes = config.PrefixConfig{
+		Types:    map[string]string{"story": "feat", "spike": "research"},
+		Labels:   map[string]string{"hotfix": "hotfix"},
+		Fallback: &fallback,
+	}
+	assert.Equal(t, "feat", prefixes.ForType("Story"), "configured types take precedence")
+	assert.Equal(t, "research", prefixes.ForType("Spike"))
+	assert.Equal(t, "bugfix", prefixes.ForType("Bug"), "defaults still apply")
+	assert.Equal(t, "misc", prefixes.ForType("Technical Debt"))
+
+	assert.Equal(t, "hotfix", prefixes.For("Bug", []string{"backend", "HotFix"}), "labels take precedence")
+	assert.Equal(t, "bugfix", prefixes.For("Bug", []string{"backend"}))
+}
+
+func TestBaseFor(t *testing.T) {
+	t.Parallel()
+
+	var cfg config.Config
+	assert.Empty(t, cfg.BaseBranch(), "the base branch is detected when it is not configured")
+	_, ok := cfg.BaseFor("Bug", "bugfix")
+	assert.False(t, ok)
+
+	cfg.BaseRules = map[string]string{"hotfix": "release/*", "bug": "develop"}
+
+	base, ok := cfg.BaseFor("Bug", "bugfix")
+	require.True(t, ok)
+	assert.Equal(t, "develop", base)
+
+	base, ok = cfg.BaseFor("Task", "hotfix")
+	require.True(t, ok, "rules also match the branch prefix")
+	assert.Equal(t, "release/*", base)
+
+	_, ok = cfg.BaseFor("Story", "feature")
+	assert.False(t, ok)
+}
+
+func TestCreateFromRemote(t *testing.T) {
+	t.Parallel()
+
+	var cfg config.Config
+	assert.False(t, cfg.CreateFromRemote(), "branches are created from the local base branch by default")
+
+	from := "Remote"
+	cfg.Create.From = &from
+	assert.True(t, cfg.CreateFromRemote())
+
+	from = config.CreateFromLocal
+	assert.False(t, cfg.CreateFromRemote())
+}
+
+func TestDirtyMode(t *testing.T) {
+	t.Parallel()
+
+	var cfg config.Config
+	assert.Equal(t, config.DirtyAbort, cfg.DirtyMode())
+
+	mode := "Stash"
+	cfg.Create.Dirty = &mode
+	assert.Equal(t, config.DirtyStash, cfg.DirtyMode())
+
+	require.NoError(t, config.ValidateDirtyMode("carry"))
+	require.ErrorContains(t, config.ValidateDirtyMode("discard"), "use abort, carry or stash")
+}
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// UserConfigFile returns the path of the user configuration file.
+func UserConfigFile() (string, error) {
+	if userConfiguration != nil && userConfiguration.ConfigFileUsed() != "" {
+		return userConfiguration.ConfigFileUsed(), nil
+	}
+
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, defaultConfigFilename+".yaml"), nil
+}
+
+// Unset removes `key` from the user configuration file. Returns false when the
+// key is not set in the file.
+func Unset(key string) (bool, error) {
+	if userConfiguration == nil {
+		return false, errors.New("configuration not initialized")
+	}
+
+	// Viper stores keys in lower case.
+	settings := userConfiguration.AllSettings()
+	if !deleteKey(settings, strings.Split(strings.ToLower(key), ".")) {
+		return false, nil
+	}
+
+	file, err := UserConfigFile()
+	if err != nil {
+		return false, err
+	}
+
+	u := viper.New()
+	u.SetConfigFile(file)
+	u.SetConfigType("yaml")
+	if err = u.MergeConfigMap(settings); err != nil {
+		return false, err
+	}
+
+	if err = u.WriteConfigAs(file); err != nil {
+		return false, err
+	}
+
+	userConfiguration = u
+	return true, nil
+}
+
+// deleteKey deletes the nested key made up of `parts` from `settings` and removes
+// the maps that are left empty. Returns false when the key does not exist.
+func deleteKey(settings map[string]any, parts []string) bool {
+	if len(parts) == 1 {
+		if _, ok := settings[parts[0]]; !ok {
+			return false
+		}
+
+		delete(settings, parts[0])
+		return true
+	}
+
+	child, ok := settings[parts[0]].(map[string]any)
+	if !ok || !deleteKey(child, parts[1:]) {
+		return false
+	}
+
+	if len(child) == 0 {
+		delete(settings, parts[0])
+	}
+
+	return true
+}
+
+// ValidateFile returns an error when the configuration file `name` can not be read,
+// or when one of the options in it has an invalid value.
+func ValidateFile(name string) error {
+	v := viper.New()
+	v.SetConfigFile(name)
+	v.SetConfigType("yaml")
+	if err := v.ReadInConfig(); err != nil {
+		return err
+	}
+
+	var cfg Config
+	if err := v.Unmarshal(&cfg); err != nil {
+		return err
+	}
+
+	keys := v.AllKeys()
+	slices.Sort(keys)
+
+	var errs []error
+	for _, key := range keys {
+		opt, ok := LookupOption(key)
+		if !ok {
+			continue
+		}
+
+		if value := opt.CurrentValue(cfg); value != nil {
+			if err := opt.Check(*value); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	// The options are also checked together, which finds the problems that are
+	// not found by checking them one by one.
+	if len(errs) == 0 {
+		if _, err := cfg.SanitizeOptions(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,84 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MaikelVeen/branch/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//nolint:paralleltest // Changes the environment and the global configuration.
+func TestUnset(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	userFile := filepath.Join(home, ".config", "branch", "config.yaml")
+	writeFile(t, userFile, "template: user\nsanitize:\n  max-words: 5\n")
+
+	_, err := config.Init()
+	require.NoError(t, err)
+
+	file, err := config.UserConfigFile()
+	require.NoError(t, err)
+	assert.Equal(t, userFile, file)
+
+	ok, err := config.Unset(config.KeySanitizeMaxWords)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = config.Unset(config.KeySanitizeMaxWords)
+	require.NoError(t, err)
+	assert.False(t, ok, "the key is no longer set")
+
+	saved, err := os.ReadFile(userFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(saved), "template: user")
+	assert.NotContains(t, string(saved), "sanitize", "empty sections are removed")
+}
+
+func TestValidateFile(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		content string
+		wantErr string
+	}{
+		"valid": {
+			content: "template: \"{{.key}}\"\nsanitize:\n  case: upper\n  max-words: 3\n",
+		},
+		"unknown keys are ignored": {
+			content: "unknown: value\n",
+		},
+		"invalid yaml": {
+			content: "template: [\n",
+			wantErr: "While parsing config",
+		},
+		"invalid value": {
+			content: "sanitize:\n  case: title\n",
+			wantErr: "invalid value for sanitize.case",
+		},
+		"invalid type": {
+			content: "sanitize:\n  max-words: many\n",
+			wantErr: "max-words",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			file := filepath.Join(t.TempDir(), "config.yaml")
+			writeFile(t, file, tc.content)
+
+			err := config.ValidateFile(file)
+			if tc.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.wantErr)
+		})
+	}
+}
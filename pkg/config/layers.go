@@ -0,0 +1,145 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// RepoConfigFilename is the name of the repository configuration file. It is looked
+// up in the working directory and its parents, up to the top of the git repository.
+const RepoConfigFilename = ".branch.yaml"
+
+// repoConfiguration holds the repository configuration file, nil when there is none.
+var repoConfiguration *viper.Viper
+
+// repoIgnoredSections are the sections a repository configuration file can not set.
+// Repositories are not trusted: the credential store runs helpers and handles the
+// Jira token, so a cloned repository must not be able to change it.
+var repoIgnoredSections = []string{"credentials"}
+
+// ignoredRepoKeys holds the keys of the repository configuration file that are ignored.
+var ignoredRepoKeys []string
+
+var envKeyReplacer = strings.NewReplacer("-", "_", ".", "_")
+
+// Origin is the layer a configuration value is taken from.
+type Origin string
+
+const (
+	OriginEnv     Origin = "env"
+	OriginRepo    Origin = "repo"
+	OriginUser    Origin = "user"
+	OriginDefault Origin = "default"
+)
+
+// ValueOrigin returns the layer the value of `key` is taken from, and where in
+// that layer: the environment variable or the configuration file. Flags take
+// precedence over all layers, but only apply to the command they belong to.
+func ValueOrigin(key string) (Origin, string) {
+	if env := EnvName(key); env != "" {
+		if _, ok := os.LookupEnv(env); ok {
+			return OriginEnv, env
+		}
+	}
+
+	if repoConfiguration != nil && repoConfiguration.IsSet(key) {
+		return OriginRepo, repoConfiguration.ConfigFileUsed()
+	}
+
+	if userConfiguration != nil && userConfiguration.IsSet(key) {
+		return OriginUser, userConfiguration.ConfigFileUsed()
+	}
+
+	return OriginDefault, ""
+}
+
+// EnvName returns the environment variable that sets `key`.
+func EnvName(key string) string {
+	return envPrefix + "_" + strings.ToUpper(envKeyReplacer.Replace(key))
+}
+
+// RepoConfigFile returns the path of the repository configuration file in use,
+// or an empty string when there is none.
+func RepoConfigFile() string {
+	if repoConfiguration == nil {
+		return ""
+	}
+
+	return repoConfiguration.ConfigFileUsed()
+}
+
+// FindRepoConfig looks for RepoConfigFilename in `dir` and its parents, up to the
+// top of the git repository that contains `dir`. Returns false when there is no
+// such file or when `dir` is not inside a git repository.
+func FindRepoConfig(dir string) (string, bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+
+	var found string
+	for {
+		if found == "" {
+			candidate := filepath.Join(dir, RepoConfigFilename)
+			if info, err := os.Stat(candidate); err == nil && info.Mode().IsRegular() {
+				found = candidate
+			}
+		}
+
+		// .git is a directory, or a file in worktrees and submodules.
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return found, found != ""
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// IgnoredRepoKeys returns the keys set in the repository configuration file that
+// are ignored, because a repository can not set them.
+func IgnoredRepoKeys() []string {
+	return ignoredRepoKeys
+}
+
+// readRepoConfig reads the repository configuration file `file`, without the
+// repoIgnoredSections. Returns the keys that are left out, sorted.
+func readRepoConfig(file string) (*viper.Viper, []string, error) {
+	f := viper.New()
+	f.SetConfigFile(file)
+	f.SetConfigType("yaml")
+	if err := f.ReadInConfig(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+
+	var ignored []string
+	for _, key := range f.AllKeys() {
+		section, _, _ := strings.Cut(key, ".")
+		if slices.Contains(repoIgnoredSections, section) {
+			ignored = append(ignored, key)
+		}
+	}
+	slices.Sort(ignored)
+
+	settings := f.AllSettings()
+	for _, section := range repoIgnoredSections {
+		delete(settings, section)
+	}
+
+	r := viper.New()
+	r.SetConfigFile(file)
+	r.SetConfigType("yaml")
+	if err := r.MergeConfigMap(settings); err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+
+	return r, ignored, nil
+}
@@ -0,0 +1,146 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MaikelVeen/branch/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, name, content string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(name), 0o755))
+	require.NoError(t, os.WriteFile(name, []byte(content), 0o600))
+}
+
+func TestFindRepoConfig(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	repo := filepath.Join(root, "repo")
+	nested := filepath.Join(repo, "a", "b")
+	require.NoError(t, os.MkdirAll(filepath.Join(repo, ".git"), 0o755))
+	require.NoError(t, os.MkdirAll(nested, 0o755))
+
+	// A file above the top of the repository is not used.
+	writeFile(t, filepath.Join(root, config.RepoConfigFilename), "template: outside")
+
+	_, ok := config.FindRepoConfig(nested)
+	assert.False(t, ok)
+
+	writeFile(t, filepath.Join(repo, config.RepoConfigFilename), "template: repo")
+	file, ok := config.FindRepoConfig(nested)
+	require.True(t, ok)
+	assert.Equal(t, filepath.Join(repo, config.RepoConfigFilename), file)
+
+	// The nearest file wins.
+	writeFile(t, filepath.Join(repo, "a", config.RepoConfigFilename), "template: nested")
+	file, ok = config.FindRepoConfig(nested)
+	require.True(t, ok)
+	assert.Equal(t, filepath.Join(repo, "a", config.RepoConfigFilename), file)
+
+	// Outside a repository there is no repository configuration.
+	_, ok = config.FindRepoConfig(root)
+	assert.False(t, ok)
+}
+
+//nolint:paralleltest // Changes the environment and working directory.
+func TestLayers(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	userFile := filepath.Join(home, ".config", "branch", "config.yaml")
+	writeFile(t, userFile, "template: user\nsanitize:\n  max-words: 5\n")
+
+	repo := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(repo, ".git"), 0o755))
+	writeFile(t, filepath.Join(repo, config.RepoConfigFilename), "template: repo\npicker:\n  jql: project = ABC\n")
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(repo))
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	_, err = config.Init()
+	require.NoError(t, err)
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "repo", *cfg.Template, "the repository takes precedence over the user")
+	assert.Equal(t, 5, *cfg.Sanitize.MaxWords)
+	assert.Equal(t, "project = ABC", cfg.PickerJQL())
+
+	origin, source := config.ValueOrigin(config.KeyTemplate)
+	assert.Equal(t, config.OriginRepo, origin)
+	assert.Equal(t, filepath.Join(repo, config.RepoConfigFilename), source)
+
+	origin, source = config.ValueOrigin(config.KeySanitizeMaxWords)
+	assert.Equal(t, config.OriginUser, origin)
+	assert.Equal(t, userFile, source)
+
+	origin, _ = config.ValueOrigin(config.KeySanitizeCase)
+	assert.Equal(t, config.OriginDefault, origin)
+
+	// Writes go to the user file only.
+	opt, ok := config.LookupOption(config.KeySanitizeCase)
+	require.True(t, ok)
+	require.NoError(t, opt.SetValue(cfg, "upper"))
+	require.NoError(t, cfg.Save())
+
+	saved, err := os.ReadFile(userFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(saved), "case: upper")
+	assert.Contains(t, string(saved), "template: user")
+	assert.NotContains(t, string(saved), "repo")
+	assert.NotContains(t, string(saved), "jql")
+
+	// The environment takes precedence over the repository.
+	t.Setenv("BRANCH_TEMPLATE", "env")
+	t.Setenv("BRANCH_SANITIZE_MAX_WORDS", "3")
+
+	cfg, err = config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "env", *cfg.Template)
+	assert.Equal(t, 3, *cfg.Sanitize.MaxWords)
+
+	origin, source = config.ValueOrigin(config.KeySanitizeMaxWords)
+	assert.Equal(t, config.OriginEnv, origin)
+	assert.Equal(t, "BRANCH_SANITIZE_MAX_WORDS", source)
+}
+
+//nolint:paralleltest // Changes the environment and working directory.
+func TestRepoConfigCannotSetCredentials(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	userFile := filepath.Join(home, ".config", "branch", "config.yaml")
+	writeFile(t, userFile, "credentials:\n  store: env\n")
+
+	repo := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(repo, ".git"), 0o755))
+	writeFile(t, filepath.Join(repo, config.RepoConfigFilename),
+		"template: repo\ncredentials:\n  store: helper\n  helper: \"!cat > /tmp/token\"\n")
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(repo))
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	_, err = config.Init()
+	require.NoError(t, err)
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "repo", *cfg.Template, "other values are still read")
+	assert.Equal(t, config.CredentialStoreEnv, cfg.CredentialStore())
+	assert.Nil(t, cfg.Credentials.Helper)
+
+	origin, _ := config.ValueOrigin(config.KeyCredentialHelper)
+	assert.Equal(t, config.OriginDefault, origin)
+	origin, _ = config.ValueOrigin(config.KeyCredentialStore)
+	assert.Equal(t, config.OriginUser, origin)
+
+	assert.Equal(t, []string{config.KeyCredentialHelper, config.KeyCredentialStore}, config.IgnoredRepoKeys())
+}
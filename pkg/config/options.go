@@ -0,0 +1,588 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/MaikelVeen/branch/pkg/git"
+)
+
+// OptionType is the type of the value of an option.
+type OptionType string
+
+const (
+	OptionString OptionType = "string"
+	OptionBool   OptionType = "bool"
+	OptionInt    OptionType = "int"
+	// OptionList values are set as comma separated values.
+	OptionList OptionType = "list"
+	// OptionMap values are set as comma separated key=value pairs.
+	OptionMap OptionType = "map"
+)
+
+// Option represents a configuration option that can be displayed to the user.
+type Option struct {
+	Key         string
+	Type        OptionType
+	Description string
+	// Default is the value used when the option is not set, formatted as it is set.
+	Default string
+	// Validate checks a value before it is set, after it is checked against the type.
+	Validate     func(value string) error
+	CurrentValue func(cfg Config) *string
+	SetValue     func(cfg *Config, value string) error
+}
+
+// Check returns an error when `value` can not be set: when it does not match the
+// type of the option or is rejected by its validator.
+func (o *Option) Check(value string) error {
+	if err := checkType(o.Type, value); err != nil {
+		return fmt.Errorf("invalid value for %s: %w", o.Key, err)
+	}
+
+	if o.Validate != nil {
+		if err := o.Validate(value); err != nil {
+			return fmt.Errorf("invalid value for %s: %w", o.Key, err)
+		}
+	}
+
+	return nil
+}
+
+// Options is a list of all available configuration options.
+var Options = map[string]*Option{}
+
+// AddValidator adds `validate` to the checks of the option with `key`. It lets
+// packages that use a value, such as the template, validate it.
+func AddValidator(key string, validate func(value string) error) {
+	opt, ok := Options[key]
+	if !ok {
+		panic("unknown configuration option " + key)
+	}
+
+	previous := opt.Validate
+	opt.Validate = func(value string) error {
+		if previous != nil {
+			if err := previous(value); err != nil {
+				return err
+			}
+		}
+
+		return validate(value)
+	}
+}
+
+func register(opt *Option) {
+	Options[opt.Key] = opt
+}
+
+func init() {
+	register(stringOption(
+		KeyTemplate,
+		"Template to use for branch name",
+		func(cfg *Config) **string { return &cfg.Template },
+		nil,
+	).withDefault(DefaultTemplate))
+
+	register(stringOption(
+		KeyBase,
+		"Branch new branches are based on and that branches are merged into, defaults to the default branch of the remote",
+		func(cfg *Config) **string { return &cfg.Base },
+		git.ValidateRefName,
+	))
+	register(mapOption(
+		KeyBaseRules,
+		"Base branches of issue types or branch prefixes, as type=branch pairs, branches can be glob patterns such as release/*",
+		func(cfg *Config) *map[string]string { return &cfg.BaseRules },
+		validateBaseRule,
+	))
+
+	for _, opt := range createOptions("create", func(cfg *Config) *CreateConfig { return &cfg.Create }) {
+		register(opt)
+	}
+
+	register(stringOption(
+		KeyCreateFrom,
+		"Where `branch create` creates branches from: local checks out the local base branch, "+
+			"remote fetches the base branch and creates the branch from the remote one",
+		func(cfg *Config) **string { return &cfg.Create.From },
+		func(value string) error {
+			switch strings.ToLower(value) {
+			case CreateFromLocal, CreateFromRemote:
+				return nil
+			default:
+				return errors.New("use local or remote")
+			}
+		},
+	).withDefault(CreateFromLocal))
+	register(stringOption(
+		KeyCreateDirty,
+		"What `branch create` does with changes in the working tree: abort, carry them over to the new branch, "+
+			"or stash them and apply them to the new branch",
+		func(cfg *Config) **string { return &cfg.Create.Dirty },
+		ValidateDirtyMode,
+	).withDefault(DirtyAbort))
+
+	register(stringOption(
+		KeyPickerJQL,
+		"JQL query selecting the issues offered by `branch create` without an issue key",
+		func(cfg *Config) **string { return &cfg.Picker.JQL },
+		nil,
+	).withDefault(DefaultPickerJQL))
+
+	register(stringOption(
+		KeyPrefixFallback,
+		"Branch prefix of issue types without a prefix, defaults to the issue type",
+		func(cfg *Config) **string { return &cfg.Prefix.Fallback },
+		validatePrefix,
+	))
+	register(mapOption(
+		prefixTypesKey,
+		"Branch prefixes of issue types, as type=prefix pairs",
+		func(cfg *Config) *map[string]string { return &cfg.Prefix.Types },
+		validatePrefix,
+	).withDefault(formatMap(DefaultTypePrefixes)))
+	register(mapOption(
+		prefixLabelsKey,
+		"Branch prefixes of labels, as label=prefix pairs, these take precedence over the issue type",
+		func(cfg *Config) *map[string]string { return &cfg.Prefix.Labels },
+		validatePrefix,
+	))
+
+	register(listOption(
+		KeyCleanProtected,
+		"Comma separated branch names or glob patterns that branch clean never deletes",
+		func(cfg *Config) *[]string { return &cfg.Clean.Protected },
+		func(value string) error {
+			for _, pattern := range parseList(value) {
+				if _, err := filepath.Match(pattern, ""); err != nil {
+					return fmt.Errorf("invalid pattern %q", pattern)
+				}
+			}
+			return nil
+		},
+	).withDefault(strings.Join(DefaultProtectedBranches, ",")))
+
+	defaults := git.DefaultSanitizeOptions()
+	register(stringOption(
+		KeySanitizeSeparator,
+		"Character that joins the words of the summary in the branch name: -, _, . or /",
+		func(cfg *Config) **string { return &cfg.Sanitize.Separator },
+		func(value string) error {
+			opts := git.DefaultSanitizeOptions()
+			opts.Separator = value
+			return opts.Validate()
+		},
+	).withDefault(defaults.Separator))
+	register(stringOption(
+		KeySanitizeCase,
+		"Case of the summary in the branch name: lower, upper or preserve",
+		func(cfg *Config) **string { return &cfg.Sanitize.Case },
+		func(value string) error {
+			opts := git.DefaultSanitizeOptions()
+			opts.Case = git.CaseMode(strings.ToLower(value))
+			return opts.Validate()
+		},
+	).withDefault(string(defaults.Case)))
+	register(intOption(
+		KeySanitizeMaxWords,
+		"Maximum number of words of the summary in the branch name, 0 for no limit",
+		func(cfg *Config) **int { return &cfg.Sanitize.MaxWords },
+	).withDefault(strconv.Itoa(defaults.MaxWords)))
+	register(intOption(
+		KeySanitizeMaxLength,
+		"Maximum length of the summary in the branch name, truncated at a word boundary, 0 for no limit",
+		func(cfg *Config) **int { return &cfg.Sanitize.MaxLength },
+	).withDefault(strconv.Itoa(defaults.MaxLength)))
+	register(listOption(
+		KeySanitizeStopWords,
+		"Comma separated words that are left out of the summary in the branch name",
+		func(cfg *Config) *[]string { return &cfg.Sanitize.StopWords },
+		nil,
+	))
+	register(boolOption(
+		KeySanitizeKeepBrackets,
+		"Keep text between brackets, such as [Bug], in the summary in the branch name",
+		func(cfg *Config) **bool { return &cfg.Sanitize.KeepBrackets },
+	).withDefault(strconv.FormatBool(defaults.KeepBrackets)))
+	register(stringOption(
+		KeySanitizeFallback,
+		"Text used when nothing is left of the summary, for example when it is written in Chinese",
+		func(cfg *Config) **string { return &cfg.Sanitize.Fallback },
+		nil,
+	))
+	register(boolOption(
+		KeySanitizeRepair,
+		"Repair branch names that git does not accept, for example because of the template, instead of failing",
+		func(cfg *Config) **bool { return &cfg.Sanitize.Repair },
+	).withDefault("false"))
+
+	register(stringOption(
+		KeyCredentialStore,
+		"Where Jira credentials are stored: keyring, file, env or helper",
+		func(cfg *Config) **string { return &cfg.Credentials.Store },
+		func(value string) error {
+			switch value {
+			case CredentialStoreKeyring, CredentialStoreFile, CredentialStoreEnv, CredentialStoreHelper:
+				return nil
+			default:
+				return errors.New("use one of keyring, file, env or helper")
+			}
+		},
+	).withDefault(CredentialStoreKeyring))
+	register(stringOption(
+		KeyCredentialHelper,
+		"Credential helper used by the helper store, a name, path or shell snippet starting with !",
+		func(cfg *Config) **string { return &cfg.Credentials.Helper },
+		nil,
+	))
+}
+
+// LookupOption returns the option for `key`. Besides the keys in Options, the
+// create options can be set per project using `projects.<PROJECT>.create.<option>`
+// and branch prefixes using `prefix.types.<type>` and `prefix.labels.<label>`.
+func LookupOption(key string) (*Option, bool) {
+	if opt, ok := Options[key]; ok {
+		return opt, true
+	}
+
+	if opt, ok := lookupPrefixOption(key); ok {
+		return opt, true
+	}
+
+	if name, ok := strings.CutPrefix(key, KeyBaseRules+"."); ok && name != "" {
+		// Viper stores keys in lower case.
+		name = strings.ToLower(name)
+		opt := mapEntryOption(KeyBaseRules+"."+name, "Base branch of the "+name+" issue type or branch prefix",
+			func(cfg *Config) *map[string]string { return &cfg.BaseRules }, name)
+		opt.Validate = validateBaseRule
+
+		return opt, true
+	}
+
+	parts := strings.SplitN(key, ".", 3)
+	if len(parts) != 3 || parts[0] != projectsKey || parts[1] == "" {
+		return nil, false
+	}
+
+	project := strings.ToLower(parts[1])
+	prefix := strings.Join([]string{projectsKey, project, "create"}, ".")
+	opts := createOptions(prefix, func(cfg *Config) *CreateConfig {
+		if cfg.Projects == nil {
+			cfg.Projects = map[string]*ProjectConfig{}
+		}
+
+		if cfg.Projects[project] == nil {
+			cfg.Projects[project] = &ProjectConfig{}
+		}
+
+		return &cfg.Projects[project].Create
+	})
+
+	for _, opt := range opts {
+		if opt.Key == strings.Join([]string{projectsKey, project, strings.ToLower(parts[2])}, ".") {
+			return opt, true
+		}
+	}
+
+	return nil, false
+}
+
+// lookupPrefixOption returns the option for the prefix of an issue type or label.
+func lookupPrefixOption(key string) (*Option, bool) {
+	for _, m := range []struct {
+		key   string
+		what  string
+		field func(cfg *Config) *map[string]string
+	}{
+		{prefixTypesKey, "issue type", func(cfg *Config) *map[string]string { return &cfg.Prefix.Types }},
+		{prefixLabelsKey, "label", func(cfg *Config) *map[string]string { return &cfg.Prefix.Labels }},
+	} {
+		name, ok := strings.CutPrefix(key, m.key+".")
+		if !ok || name == "" {
+			continue
+		}
+
+		// Viper stores keys in lower case.
+		name = strings.ToLower(name)
+		opt := mapEntryOption(m.key+"."+name, "Branch prefix of the "+name+" "+m.what, m.field, name)
+		opt.Validate = validatePrefix
+		if m.key == prefixTypesKey {
+			opt.Default = DefaultTypePrefixes[name]
+		}
+
+		return opt, true
+	}
+
+	return nil, false
+}
+
+// createOptions returns the options of a CreateConfig stored under `prefix`.
+func createOptions(prefix string, create func(cfg *Config) *CreateConfig) []*Option {
+	transitionKey := prefix + ".transition"
+	assignKey := prefix + ".assign"
+	commentKey := prefix + ".comment"
+
+	return []*Option{
+		stringOption(transitionKey, "Transition or status to move the issue to after creating a branch",
+			func(cfg *Config) **string { return &create(cfg).Transition }, nil),
+		boolOption(assignKey, "Assign the issue to yourself after creating a branch",
+			func(cfg *Config) **bool { return &create(cfg).Assign }).withDefault("false"),
+		boolOption(commentKey, "Comment the branch name on the issue after creating a branch",
+			func(cfg *Config) **bool { return &create(cfg).Comment }).withDefault("false"),
+	}
+}
+
+// validatePrefix checks that `value` can be used as the prefix of a branch name.
+func validatePrefix(value string) error {
+	for _, entry := range parseList(value) {
+		if _, prefix, ok := strings.Cut(entry, "="); ok {
+			entry = prefix
+		}
+
+		if err := git.ValidateRefName(entry); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateBaseRule checks that `value` is a branch name or a glob pattern of branch names.
+func validateBaseRule(value string) error {
+	for _, entry := range parseList(value) {
+		if _, base, ok := strings.Cut(entry, "="); ok {
+			entry = base
+		}
+
+		if !git.IsBranchPattern(entry) {
+			if err := git.ValidateRefName(entry); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if _, err := filepath.Match(entry, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q", entry)
+		}
+	}
+
+	return nil
+}
+
+// newOption returns an option of type `typ`, whose values are checked before `set` is called.
+func newOption(
+	key string,
+	typ OptionType,
+	description string,
+	current func(cfg Config) *string,
+	set func(cfg *Config, value string),
+) *Option {
+	opt := &Option{Key: key, Type: typ, Description: description, CurrentValue: current}
+	opt.SetValue = func(cfg *Config, value string) error {
+		if err := opt.Check(value); err != nil {
+			return err
+		}
+
+		set(cfg, value)
+		return nil
+	}
+
+	return opt
+}
+
+// withDefault sets the default value of the option and returns it.
+func (o *Option) withDefault(value string) *Option {
+	o.Default = value
+	return o
+}
+
+// stringOption returns an option for the string field returned by `field`.
+// The optional `validate` function is called before the value is set.
+func stringOption(key, description string, field func(cfg *Config) **string, validate func(string) error) *Option {
+	opt := newOption(key, OptionString, description,
+		func(cfg Config) *string { return *field(&cfg) },
+		func(cfg *Config, value string) {
+			*field(cfg) = &value
+			userConfiguration.Set(key, value)
+		},
+	)
+	opt.Validate = validate
+
+	return opt
+}
+
+// boolOption returns an option for the boolean field returned by `field`.
+func boolOption(key, description string, field func(cfg *Config) **bool) *Option {
+	return newOption(key, OptionBool, description,
+		func(cfg Config) *string {
+			b := *field(&cfg)
+			if b == nil {
+				return nil
+			}
+
+			s := strconv.FormatBool(*b)
+			return &s
+		},
+		func(cfg *Config, value string) {
+			b, _ := strconv.ParseBool(value)
+			*field(cfg) = &b
+			userConfiguration.Set(key, b)
+		},
+	)
+}
+
+// intOption returns an option for the non negative integer field returned by `field`.
+func intOption(key, description string, field func(cfg *Config) **int) *Option {
+	return newOption(key, OptionInt, description,
+		func(cfg Config) *string {
+			i := *field(&cfg)
+			if i == nil {
+				return nil
+			}
+
+			s := strconv.Itoa(*i)
+			return &s
+		},
+		func(cfg *Config, value string) {
+			i, _ := strconv.Atoi(value)
+			*field(cfg) = &i
+			userConfiguration.Set(key, i)
+		},
+	)
+}
+
+// listOption returns an option for the list field returned by `field`,
+// which is set from a comma separated value.
+func listOption(key, description string, field func(cfg *Config) *[]string, validate func(string) error) *Option {
+	opt := newOption(key, OptionList, description,
+		func(cfg Config) *string {
+			l := *field(&cfg)
+			if l == nil {
+				return nil
+			}
+
+			s := strings.Join(l, ",")
+			return &s
+		},
+		func(cfg *Config, value string) {
+			l := parseList(value)
+			*field(cfg) = l
+			userConfiguration.Set(key, l)
+		},
+	)
+	opt.Validate = validate
+
+	return opt
+}
+
+// mapOption returns an option for the map field returned by `field`, which is
+// set from comma separated key=value pairs. Keys are stored in lower case.
+func mapOption(key, description string, field func(cfg *Config) *map[string]string, validate func(string) error) *Option {
+	opt := newOption(key, OptionMap, description,
+		func(cfg Config) *string {
+			m := *field(&cfg)
+			if m == nil {
+				return nil
+			}
+
+			s := formatMap(m)
+			return &s
+		},
+		func(cfg *Config, value string) {
+			m, _ := parseMap(value)
+			*field(cfg) = m
+			userConfiguration.Set(key, m)
+		},
+	)
+	opt.Validate = validate
+
+	return opt
+}
+
+// mapEntryOption returns an option for `entry` of the map field returned by `field`.
+func mapEntryOption(key, description string, field func(cfg *Config) *map[string]string, entry string) *Option {
+	return newOption(key, OptionString, description,
+		func(cfg Config) *string {
+			v, ok := (*field(&cfg))[entry]
+			if !ok {
+				return nil
+			}
+
+			return &v
+		},
+		func(cfg *Config, value string) {
+			m := field(cfg)
+			if *m == nil {
+				*m = map[string]string{}
+			}
+
+			(*m)[entry] = value
+			userConfiguration.Set(key, value)
+		},
+	)
+}
+
+// checkType returns an error when `value` is not a valid value of type `typ`.
+func checkType(typ OptionType, value string) error {
+	switch typ {
+	case OptionBool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return errors.New("must be true or false")
+		}
+	case OptionInt:
+		if i, err := strconv.Atoi(value); err != nil || i < 0 {
+			return errors.New("must be a number of at least 0")
+		}
+	case OptionMap:
+		if _, err := parseMap(value); err != nil {
+			return err
+		}
+	case OptionString, OptionList:
+	}
+
+	return nil
+}
+
+// parseList splits a comma separated value, leaving out empty values.
+func parseList(value string) []string {
+	l := []string{}
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			l = append(l, v)
+		}
+	}
+
+	return l
+}
+
+// parseMap parses comma separated key=value pairs.
+func parseMap(value string) (map[string]string, error) {
+	m := map[string]string{}
+	for _, pair := range parseList(value) {
+		k, v, ok := strings.Cut(pair, "=")
+		k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+		if !ok || k == "" {
+			return nil, fmt.Errorf("%q is not a key=value pair", pair)
+		}
+
+		m[strings.ToLower(k)] = v
+	}
+
+	return m, nil
+}
+
+// formatMap formats `m` as comma separated key=value pairs, sorted by key.
+func formatMap(m map[string]string) string {
+	pairs := make([]string, 0, len(m))
+	for k, v := range m {
+		pairs = append(pairs, k+"="+v)
+	}
+	slices.Sort(pairs)
+
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,135 @@
+package config_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/MaikelVeen/branch/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptionsRegistry(t *testing.T) {
+	t.Parallel()
+
+	for key, opt := range config.Options {
+		assert.Equal(t, key, opt.Key)
+		assert.NotEmpty(t, opt.Type, key)
+		assert.NotEmpty(t, opt.Description, key)
+		assert.NotNil(t, opt.CurrentValue, key)
+		assert.NotNil(t, opt.SetValue, key)
+
+		if opt.Default != "" {
+			require.NoError(t, opt.Check(opt.Default), "the default of %s is valid", key)
+		}
+	}
+
+	base, ok := config.LookupOption(config.KeyBase)
+	require.True(t, ok)
+	assert.Empty(t, base.Default, "the base branch defaults to the default branch of the remote")
+}
+
+func TestOptionCheck(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		key     string
+		valid   []string
+		invalid []string
+	}{
+		"string with validator": {
+			key:     config.KeySanitizeCase,
+			valid:   []string{"lower", "UPPER", "preserve"},
+			invalid: []string{"title"},
+		},
+		"base branch": {
+			key:     config.KeyBase,
+			valid:   []string{"main", "release/2024"},
+			invalid: []string{"", "feature..x", "-main", "a b"},
+		},
+		"base rules": {
+			key:     config.KeyBaseRules,
+			valid:   []string{"hotfix=release/*", "bug=develop, story=main", ""},
+			invalid: []string{"hotfix=release/[", "bug=dev..elop", "bug"},
+		},
+		"base rule": {
+			key:     "base-rules.hotfix",
+			valid:   []string{"release/*", "release/v[0-9]*", "develop"},
+			invalid: []string{"release/[", "-develop"},
+		},
+		"create from": {
+			key:     config.KeyCreateFrom,
+			valid:   []string{"local", "remote", "Remote"},
+			invalid: []string{"", "origin"},
+		},
+		"create dirty": {
+			key:     config.KeyCreateDirty,
+			valid:   []string{"abort", "carry", "Stash"},
+			invalid: []string{"", "discard"},
+		},
+		"bool": {
+			key:     config.KeySanitizeKeepBrackets,
+			valid:   []string{"true", "false", "1"},
+			invalid: []string{"maybe", ""},
+		},
+		"int": {
+			key:     config.KeySanitizeMaxWords,
+			valid:   []string{"0", "8"},
+			invalid: []string{"-1", "eight"},
+		},
+		"list": {
+			key:     config.KeyCleanProtected,
+			valid:   []string{"main", "main, release/*", ""},
+			invalid: []string{"main,[x"},
+		},
+		"map": {
+			key:     "prefix.types",
+			valid:   []string{"story=feat", "Story=feat, bug=fix", ""},
+			invalid: []string{"story", "=feat", "story=fe~at"},
+		},
+		"map entry": {
+			key:     "prefix.labels.hotfix",
+			valid:   []string{"hotfix", "fix/urgent"},
+			invalid: []string{"hot fix", "-fix"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			opt, ok := config.LookupOption(tc.key)
+			require.True(t, ok)
+
+			for _, v := range tc.valid {
+				require.NoError(t, opt.Check(v), v)
+			}
+
+			for _, v := range tc.invalid {
+				require.ErrorContains(t, opt.Check(v), "invalid value for "+tc.key, v)
+
+				// Invalid values are rejected before they are set.
+				var cfg config.Config
+				require.Error(t, opt.SetValue(&cfg, v), v)
+				assert.Nil(t, opt.CurrentValue(cfg), v)
+			}
+		})
+	}
+}
+
+//nolint:paralleltest // Changes the global options.
+func TestAddValidator(t *testing.T) {
+	opt, ok := config.LookupOption(config.KeyPickerJQL)
+	require.True(t, ok)
+
+	// Validators are chained, the existing ones keep working.
+	config.AddValidator(config.KeyPickerJQL, func(value string) error {
+		if value == "reject me" {
+			return errors.New("rejected")
+		}
+		return nil
+	})
+
+	require.NoError(t, opt.Check("project = ABC"))
+	require.EqualError(t, opt.Check("reject me"), "invalid value for picker.jql: rejected")
+}
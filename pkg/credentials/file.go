@@ -0,0 +1,195 @@
+package credentials
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"filippo.io/age"
+)
+
+// FileStore stores secrets in a file encrypted with a passphrase using age and
+// scrypt. It is meant for machines without a keyring, such as containers.
+//
+// https://age-encryption.org/v1
+type FileStore struct {
+	path   string
+	prompt func() (string, error)
+
+	mu         sync.Mutex
+	passphrase string
+	secrets    map[string]string
+}
+
+// NewFileStore returns a store that keeps secrets in the file at `path`. The
+// `passphrase` function is called once, the first time the file is accessed.
+// The file is decrypted once, as key derivation is deliberately slow.
+func NewFileStore(path string, passphrase func() (string, error)) *FileStore {
+	return &FileStore{path: path, prompt: passphrase}
+}
+
+func (f *FileStore) Get(key string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	secrets, err := f.read()
+	if err != nil {
+		return "", err
+	}
+
+	secret, ok := secrets[key]
+	if !ok {
+		return "", ErrNotFound
+	}
+
+	return secret, nil
+}
+
+func (f *FileStore) Set(key, secret string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	secrets, err := f.read()
+	if err != nil {
+		return err
+	}
+
+	// Modify a copy so the cache is left untouched when writing fails.
+	secrets = maps.Clone(secrets)
+	secrets[key] = secret
+	return f.write(secrets)
+}
+
+func (f *FileStore) Delete(key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	secrets, err := f.read()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := secrets[key]; !ok {
+		return ErrNotFound
+	}
+
+	secrets = maps.Clone(secrets)
+	delete(secrets, key)
+	return f.write(secrets)
+}
+
+func (f *FileStore) getPassphrase() (string, error) {
+	if f.passphrase != "" {
+		return f.passphrase, nil
+	}
+
+	passphrase, err := f.prompt()
+	if err != nil {
+		return "", err
+	}
+
+	if passphrase == "" {
+		return "", errors.New("a passphrase is required for the encrypted credential file")
+	}
+
+	f.passphrase = passphrase
+	return passphrase, nil
+}
+
+// read decrypts the file, a missing file is treated as an empty store.
+func (f *FileStore) read() (map[string]string, error) {
+	if f.secrets != nil {
+		return f.secrets, nil
+	}
+
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			f.secrets = map[string]string{}
+			return f.secrets, nil
+		}
+		return nil, err
+	}
+
+	passphrase, err := f.getPassphrase()
+	if err != nil {
+		return nil, err
+	}
+
+	identity, err := age.NewScryptIdentity(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := age.Decrypt(bytes.NewReader(data), identity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt %s, is the passphrase correct? %w", f.path, err)
+	}
+
+	plain, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := map[string]string{}
+	if err = json.Unmarshal(plain, &secrets); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s: %w", f.path, err)
+	}
+
+	f.secrets = secrets
+	return secrets, nil
+}
+
+// write encrypts `secrets` and atomically replaces the file.
+func (f *FileStore) write(secrets map[string]string) error {
+	passphrase, err := f.getPassphrase()
+	if err != nil {
+		return err
+	}
+
+	recipient, err := age.NewScryptRecipient(passphrase)
+	if err != nil {
+		return err
+	}
+
+	plain, err := json.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	w, err := age.Encrypt(&buf, recipient)
+	if err != nil {
+		return err
+	}
+
+	if _, err = w.Write(plain); err != nil {
+		return err
+	}
+
+	if err = w.Close(); err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(f.path), 0700); err != nil {
+		return err
+	}
+
+	tmp := f.path + ".tmp"
+	if err = os.WriteFile(tmp, buf.Bytes(), 0600); err != nil {
+		return err
+	}
+
+	if err = os.Rename(tmp, f.path); err != nil {
+		return err
+	}
+
+	f.secrets = secrets
+	return nil
+}
@@ -0,0 +1,77 @@
+package credentials_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MaikelVeen/branch/pkg/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStore(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "branch", "credentials.age")
+	passphrase := func(p string) func() (string, error) {
+		return func() (string, error) { return p, nil }
+	}
+
+	s := credentials.NewFileStore(path, passphrase("correct horse"))
+
+	_, err := s.Get("missing")
+	require.ErrorIs(t, err, credentials.ErrNotFound, "a missing file is an empty store")
+
+	require.NoError(t, s.Set("branch", "secret"))
+	require.NoError(t, s.Set("other", "value"))
+
+	secret, err := s.Get("branch")
+	require.NoError(t, err)
+	assert.Equal(t, "secret", secret)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "secret", "the file is encrypted")
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	t.Run("reopened with the same passphrase", func(t *testing.T) {
+		t.Parallel()
+
+		secret, err := credentials.NewFileStore(path, passphrase("correct horse")).Get("other")
+		require.NoError(t, err)
+		assert.Equal(t, "value", secret)
+	})
+
+	t.Run("wrong passphrase", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := credentials.NewFileStore(path, passphrase("wrong")).Get("branch")
+		require.ErrorContains(t, err, "passphrase")
+	})
+
+	t.Run("empty passphrase", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := credentials.NewFileStore(path, passphrase("")).Get("branch")
+		require.Error(t, err)
+	})
+}
+
+func TestFileStoreDelete(t *testing.T) {
+	t.Parallel()
+
+	s := credentials.NewFileStore(filepath.Join(t.TempDir(), "credentials.age"), func() (string, error) {
+		return "passphrase", nil
+	})
+
+	require.NoError(t, s.Set("key", "value"))
+	require.NoError(t, s.Delete("key"))
+	require.ErrorIs(t, s.Delete("key"), credentials.ErrNotFound)
+
+	_, err := s.Get("key")
+	require.ErrorIs(t, err, credentials.ErrNotFound)
+}
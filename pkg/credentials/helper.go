@@ -0,0 +1,109 @@
+package credentials
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// HelperPrefix is prepended to helper names that are not a path or shell snippet.
+const HelperPrefix = "branch-credential-"
+
+// HelperStore delegates to an external executable, similar to git credential helpers.
+//
+// The helper is invoked with a single argument, the operation: `get`, `store` or
+// `erase`. The request is written to its stdin as `name=value` lines terminated
+// by a blank line, containing `key` and, for `store`, `secret`. For `get` the helper
+// prints `secret=<value>` to stdout, or nothing when it has no secret for the key.
+// Like `get`, `erase` is silent, so Delete asks for the secret first to return
+// ErrNotFound for keys the helper has no secret for.
+//
+// Like git, a helper name without a path separator refers to an executable called
+// branch-credential-<name> on the PATH, and a name starting with `!` is run by the shell.
+//
+// https://git-scm.com/docs/gitcredentials#_custom_helpers
+type HelperStore struct {
+	helper string
+}
+
+// NewHelperStore returns a store that uses the credential helper `helper`.
+func NewHelperStore(helper string) *HelperStore {
+	return &HelperStore{helper: helper}
+}
+
+func (h *HelperStore) Get(key string) (string, error) {
+	out, err := h.run("get", map[string]string{"key": key})
+	if err != nil {
+		return "", err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		name, value, ok := strings.Cut(scanner.Text(), "=")
+		if ok && name == "secret" {
+			return value, nil
+		}
+	}
+
+	return "", ErrNotFound
+}
+
+func (h *HelperStore) Set(key, secret string) error {
+	if strings.ContainsAny(secret, "\n\x00") {
+		return errors.New("secret contains characters that can not be passed to a credential helper")
+	}
+
+	_, err := h.run("store", map[string]string{"key": key, "secret": secret})
+	return err
+}
+
+func (h *HelperStore) Delete(key string) error {
+	if _, err := h.Get(key); err != nil {
+		return err
+	}
+
+	_, err := h.run("erase", map[string]string{"key": key})
+	return err
+}
+
+func (h *HelperStore) command(operation string) *exec.Cmd {
+	switch {
+	case strings.HasPrefix(h.helper, "!"):
+		return exec.Command("sh", "-c", h.helper[1:]+` "$@"`, h.helper[1:], operation)
+	case strings.ContainsRune(h.helper, filepath.Separator) || strings.ContainsRune(h.helper, '/'):
+		return exec.Command(h.helper, operation)
+	default:
+		return exec.Command(HelperPrefix+h.helper, operation)
+	}
+}
+
+func (h *HelperStore) run(operation string, attrs map[string]string) ([]byte, error) {
+	var in bytes.Buffer
+	// Write the key first so helpers can rely on the order.
+	for _, name := range []string{"key", "secret"} {
+		if value, ok := attrs[name]; ok {
+			fmt.Fprintf(&in, "%s=%s\n", name, value)
+		}
+	}
+	in.WriteString("\n")
+
+	var stderr bytes.Buffer
+	cmd := h.command(operation)
+	cmd.Stdin = &in
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return nil, fmt.Errorf("credential helper %s %s failed: %s", h.helper, operation, msg)
+	}
+
+	return out, nil
+}
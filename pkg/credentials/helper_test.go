@@ -0,0 +1,76 @@
+package credentials_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MaikelVeen/branch/pkg/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// helperScript stores secrets as files in a directory, one per key.
+const helperScript = `#!/bin/sh
+dir="$(dirname "$0")/secrets"
+mkdir -p "$dir"
+while IFS='=' read -r name value; do
+	[ -z "$name" ] && break
+	eval "$name=\$value"
+done
+case "$1" in
+	get) if [ -f "$dir/$key" ]; then printf 'secret=%s\n' "$(cat "$dir/$key")"; fi ;;
+	store) printf '%s' "$secret" > "$dir/$key" ;;
+	erase) rm -f "$dir/$key" ;;
+	*) echo "unknown operation $1" >&2; exit 1 ;;
+esac
+`
+
+func TestHelperStore(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	helper := filepath.Join(dir, "helper")
+	require.NoError(t, os.WriteFile(helper, []byte(helperScript), 0700))
+
+	for name, spec := range map[string]string{
+		"path":          helper,
+		"shell snippet": "!" + helper,
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			key := "profile:" + name
+			s := credentials.NewHelperStore(spec)
+
+			_, err := s.Get(key)
+			require.ErrorIs(t, err, credentials.ErrNotFound)
+
+			require.NoError(t, s.Set(key, `{"token":"a=b"}`))
+
+			secret, err := s.Get(key)
+			require.NoError(t, err)
+			assert.Equal(t, `{"token":"a=b"}`, secret)
+
+			require.NoError(t, s.Delete(key))
+
+			_, err = s.Get(key)
+			require.ErrorIs(t, err, credentials.ErrNotFound)
+
+			require.ErrorIs(t, s.Delete(key), credentials.ErrNotFound, "deleting a missing key")
+		})
+	}
+
+	t.Run("failing helper", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := credentials.NewHelperStore("!echo broken >&2; exit 1").Get("key")
+		require.ErrorContains(t, err, "broken")
+	})
+
+	t.Run("secrets with newlines are rejected", func(t *testing.T) {
+		t.Parallel()
+
+		require.Error(t, credentials.NewHelperStore(helper).Set("key", "a\nb"))
+	})
+}
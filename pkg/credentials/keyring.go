@@ -0,0 +1,50 @@
+package credentials
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/zalando/go-keyring"
+)
+
+// KeyringStore stores secrets in the keyring of the operating system, such as the
+// macOS Keychain, the Windows Credential Manager or a Secret Service on Linux.
+type KeyringStore struct {
+	service string
+}
+
+// NewKeyringStore returns a store that keeps secrets under `service` in the keyring.
+func NewKeyringStore(service string) *KeyringStore {
+	return &KeyringStore{service: service}
+}
+
+func (k *KeyringStore) Get(key string) (string, error) {
+	secret, err := keyring.Get(k.service, key)
+	if err != nil {
+		if errors.Is(err, keyring.ErrNotFound) {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("failed to get keyring: %w", err)
+	}
+
+	return secret, nil
+}
+
+func (k *KeyringStore) Set(key, secret string) error {
+	if err := keyring.Set(k.service, key, secret); err != nil {
+		return fmt.Errorf("failed to set keyring: %w", err)
+	}
+
+	return nil
+}
+
+func (k *KeyringStore) Delete(key string) error {
+	if err := keyring.Delete(k.service, key); err != nil {
+		if errors.Is(err, keyring.ErrNotFound) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("failed to delete keyring entry: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,22 @@
+// Package credentials provides backends to persist secrets such as Jira tokens.
+package credentials
+
+import "errors"
+
+var (
+	// ErrNotFound is returned when no secret is stored under a key.
+	ErrNotFound = errors.New("credential not found")
+	// ErrReadOnly is returned when writing to a store that can not be written to.
+	ErrReadOnly = errors.New("credential store is read-only")
+)
+
+// Store persists secrets identified by a key.
+type Store interface {
+	// Get returns the secret stored under `key`, or ErrNotFound.
+	Get(key string) (string, error)
+	// Set stores `secret` under `key`, replacing any existing secret.
+	Set(key, secret string) error
+	// Delete removes the secret stored under `key`. Deleting a key that
+	// does not exist returns ErrNotFound.
+	Delete(key string) error
+}
@@ -1,7 +1,10 @@
 package git
 
 import (
+	"errors"
+	"fmt"
 	"regexp"
+	"slices"
 	"strings"
 )
 
@@ -9,34 +12,202 @@ const (
 	MaxParts = 12
 )
 
+// CaseMode controls the case of a sanitized string.
+type CaseMode string
+
+const (
+	CaseLower    CaseMode = "lower"
+	CaseUpper    CaseMode = "upper"
+	CasePreserve CaseMode = "preserve"
+)
+
+// Separators lists the characters that can join the words of a sanitized string.
+var Separators = []string{"-", "_", ".", "/"}
+
+var (
+	bracketsRe = regexp.MustCompile(`([\(\[]).*?([\)\]])`)
+	specialRe  = regexp.MustCompile(`[^a-zA-Z\d\s]`)
+)
+
+// Replacement replaces the matches of the regular expression Pattern with Replace,
+// which can refer to capture groups as in regexp.Regexp.ReplaceAllString.
+type Replacement struct {
+	Pattern string
+	Replace string
+}
+
+// SanitizeOptions controls how Sanitize turns free text into a part of a refname.
+type SanitizeOptions struct {
+	// Separator joins the words, one of Separators.
+	Separator string
+	// Case is the case of the result.
+	Case CaseMode
+	// MaxWords is the maximum number of words, 0 means no limit.
+	MaxWords int
+	// MaxLength is the maximum length of the result, 0 means no limit. The result is
+	// truncated at a word boundary unless the first word is longer than the limit.
+	MaxLength int
+	// StopWords are removed, compared case insensitively.
+	StopWords []string
+	// KeepBrackets keeps the text between brackets, such as [Bug] or (backend).
+	KeepBrackets bool
+	// Replacements are applied in order to the input, before anything else.
+	Replacements []Replacement
+	// Fallback is sanitized and used when nothing is left of the input,
+	// for example because it only contains CJK characters.
+	Fallback string
+}
+
+// DefaultSanitizeOptions returns the options used by FormatAsValidRef.
+func DefaultSanitizeOptions() SanitizeOptions {
+	return SanitizeOptions{
+		Separator: "-",
+		Case:      CaseLower,
+		MaxWords:  MaxParts,
+	}
+}
+
+// Validate returns an error when the options can not be used.
+func (o *SanitizeOptions) Validate() error {
+	if _, err := o.compileReplacements(); err != nil {
+		return err
+	}
+
+	if !slices.Contains(Separators, o.Separator) {
+		return fmt.Errorf("invalid separator %q, use one of %s", o.Separator, strings.Join(Separators, " "))
+	}
+
+	switch o.Case {
+	case CaseLower, CaseUpper, CasePreserve:
+	default:
+		return fmt.Errorf("invalid case %q, use lower, upper or preserve", o.Case)
+	}
+
+	if o.MaxWords < 0 || o.MaxLength < 0 {
+		return errors.New("the maximum number of words and length can not be negative")
+	}
+
+	return nil
+}
+
 // FormatAsValidRef transforms `s` to a string that is a valid refname
 //
 // A reference is used in git to specify branches and tags. The following rules
 // must be followed when is comes to naming references:
 // https://git-scm.com/docs/git-check-ref-format
 func FormatAsValidRef(s string) string {
+	// The default options are valid.
+	out, _ := Sanitize(s, DefaultSanitizeOptions())
+	return out
+}
+
+// Sanitize transforms `s` into a string that can be used as part of a refname,
+// following `opts`. Returns an error when the options are invalid.
+func Sanitize(s string, opts SanitizeOptions) (string, error) {
+	if err := opts.Validate(); err != nil {
+		return "", err
+	}
+
+	replacements, _ := opts.compileReplacements()
+	for i, re := range replacements {
+		s = re.ReplaceAllString(s, opts.Replacements[i].Replace)
+	}
+
+	s = Transliterate(s)
+
 	// Remove everything inside () and [] to remove tags.
-	innerValRe := regexp.MustCompile(`([\(\[]).*?([\)\]])`)
-	s = innerValRe.ReplaceAllString(s, "")
+	if !opts.KeepBrackets {
+		s = bracketsRe.ReplaceAllString(s, "")
+	}
+
+	// Replace all non alpha numeric chars by whitespace and split into words.
+	words := strings.Fields(specialRe.ReplaceAllString(s, " "))
+	words = removeStopWords(words, opts.StopWords)
+
+	if opts.MaxWords > 0 {
+		words = words[:GetLengthWithUpperbound(words, opts.MaxWords)]
+	}
+
+	out := joinWithMaxLength(words, opts.Separator, opts.MaxLength)
+
+	switch opts.Case {
+	case CaseLower:
+		out = strings.ToLower(out)
+	case CaseUpper:
+		out = strings.ToUpper(out)
+	}
+
+	if out == "" && opts.Fallback != "" {
+		fallback := opts
+		fallback.Fallback = ""
+		return Sanitize(opts.Fallback, fallback)
+	}
+
+	return out, nil
+}
+
+// TrimDanglingSeparators removes the separators at the start and end of each
+// slash separated component of `name` and drops empty components. It cleans up
+// names such as "bug/ABC-1-" that result from a template with an empty value.
+func TrimDanglingSeparators(name string) string {
+	parts := strings.Split(name, "/")
 
-	// Remove all non alpha numeric chars expect whitespace.
-	special := regexp.MustCompile(`[^a-zA-Z\d\s]`)
-	s = special.ReplaceAllString(s, " ")
+	kept := parts[:0]
+	for _, p := range parts {
+		if p = strings.Trim(p, "-_."); p != "" {
+			kept = append(kept, p)
+		}
+	}
+
+	return strings.Join(kept, "/")
+}
+
+func (o *SanitizeOptions) compileReplacements() ([]*regexp.Regexp, error) {
+	res := make([]*regexp.Regexp, 0, len(o.Replacements))
+	for _, r := range o.Replacements {
+		re, err := regexp.Compile(r.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid replacement pattern %q: %w", r.Pattern, err)
+		}
+		res = append(res, re)
+	}
+
+	return res, nil
+}
 
-	// Remove leading and trailing whitespace.
-	s = strings.TrimSpace(s)
-	s = strings.ToLower(s)
+func removeStopWords(words, stopWords []string) []string {
+	if len(stopWords) == 0 {
+		return words
+	}
+
+	return slices.DeleteFunc(words, func(w string) bool {
+		return slices.ContainsFunc(stopWords, func(sw string) bool { return strings.EqualFold(w, sw) })
+	})
+}
 
-	// Remove spaces with single space.
-	d := regexp.MustCompile(`\s\s+`)
-	s = d.ReplaceAllString(s, " ")
+// joinWithMaxLength joins `words` with `sep`, leaving out the words that would
+// make the result longer than `limit`. A first word longer than `limit` is cut off.
+func joinWithMaxLength(words []string, sep string, limit int) string {
+	joined := strings.Join(words, sep)
+	if limit <= 0 || len(joined) <= limit {
+		return joined
+	}
 
-	// Split the string on the spaces.
-	parts := strings.Split(s, " ")
-	cutoff := GetLengthWithUpperbound(parts, MaxParts)
+	if len(words[0]) >= limit {
+		return words[0][:limit]
+	}
+
+	var b strings.Builder
+	b.WriteString(words[0])
+	for _, w := range words[1:] {
+		if b.Len()+len(sep)+len(w) > limit {
+			break
+		}
+		b.WriteString(sep)
+		b.WriteString(w)
+	}
 
-	// Return hyphenated string
-	return strings.Join(parts[:cutoff], "-")
+	return b.String()
 }
 
 func GetLengthWithUpperbound(s []string, m int) int {
@@ -6,6 +6,7 @@ import (
 
 	"github.com/MaikelVeen/branch/pkg/git"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFilter(t *testing.T) {
@@ -77,3 +78,109 @@ func TestFilter(t *testing.T) {
 		}
 	})
 }
+
+func TestSanitize(t *testing.T) {
+	t.Parallel()
+
+	withDefaults := func(fn func(o *git.SanitizeOptions)) git.SanitizeOptions {
+		o := git.DefaultSanitizeOptions()
+		fn(&o)
+		return o
+	}
+
+	testCases := map[string]struct {
+		input  string
+		opts   git.SanitizeOptions
+		expect string
+	}{
+		"underscore separator": {
+			input:  "Fix the login page",
+			opts:   withDefaults(func(o *git.SanitizeOptions) { o.Separator = "_" }),
+			expect: "fix_the_login_page",
+		},
+		"preserve case": {
+			input:  "Fix the API client",
+			opts:   withDefaults(func(o *git.SanitizeOptions) { o.Case = git.CasePreserve }),
+			expect: "Fix-the-API-client",
+		},
+		"upper case": {
+			input:  "Fix the API client",
+			opts:   withDefaults(func(o *git.SanitizeOptions) { o.Case = git.CaseUpper }),
+			expect: "FIX-THE-API-CLIENT",
+		},
+		"max words": {
+			input:  "one two three four",
+			opts:   withDefaults(func(o *git.SanitizeOptions) { o.MaxWords = 2 }),
+			expect: "one-two",
+		},
+		"no word limit": {
+			input:  "a b c d e f g h i j k l m n",
+			opts:   withDefaults(func(o *git.SanitizeOptions) { o.MaxWords = 0 }),
+			expect: "a-b-c-d-e-f-g-h-i-j-k-l-m-n",
+		},
+		"max length truncates at a word boundary": {
+			input:  "improve the performance of search",
+			opts:   withDefaults(func(o *git.SanitizeOptions) { o.MaxLength = 20 }),
+			expect: "improve-the",
+		},
+		"max length cuts a long first word": {
+			input:  "internationalization support",
+			opts:   withDefaults(func(o *git.SanitizeOptions) { o.MaxLength = 8 }),
+			expect: "internat",
+		},
+		"stop words are removed": {
+			input:  "Fix the bug in the parser",
+			opts:   withDefaults(func(o *git.SanitizeOptions) { o.StopWords = []string{"the", "IN"} }),
+			expect: "fix-bug-parser",
+		},
+		"keep brackets": {
+			input:  "[Backend] Fix the parser",
+			opts:   withDefaults(func(o *git.SanitizeOptions) { o.KeepBrackets = true }),
+			expect: "backend-fix-the-parser",
+		},
+		"replacements are applied first": {
+			input: "Support C++ & C#",
+			opts: withDefaults(func(o *git.SanitizeOptions) {
+				o.Replacements = []git.Replacement{
+					{Pattern: `C\+\+`, Replace: "cpp"},
+					{Pattern: `C#`, Replace: "csharp"},
+					{Pattern: `&`, Replace: "and"},
+				}
+			}),
+			expect: "support-cpp-and-csharp",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := git.Sanitize(tc.input, tc.opts)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expect, got)
+		})
+	}
+}
+
+func TestSanitizeOptionsValidate(t *testing.T) {
+	t.Parallel()
+
+	valid := git.DefaultSanitizeOptions()
+	require.NoError(t, valid.Validate())
+
+	invalid := map[string]func(o *git.SanitizeOptions){
+		"separator":   func(o *git.SanitizeOptions) { o.Separator = " " },
+		"case":        func(o *git.SanitizeOptions) { o.Case = "title" },
+		"max words":   func(o *git.SanitizeOptions) { o.MaxWords = -1 },
+		"replacement": func(o *git.SanitizeOptions) { o.Replacements = []git.Replacement{{Pattern: "("}} },
+	}
+
+	for name, fn := range invalid {
+		o := git.DefaultSanitizeOptions()
+		fn(&o)
+		require.Error(t, o.Validate(), name)
+
+		_, err := git.Sanitize("summary", o)
+		require.Error(t, err, name)
+	}
+}
@@ -96,3 +96,17 @@ func (g *Commander) ShortSymbolicRef(ctx ExecContext) (string, error) {
 
 	return strings.TrimSpace(string(out)), nil
 }
+
+// Config executes `git config <args>` and returns the trimmed output.
+// Returns an error if the command fails, which includes the case where
+// a key that is looked up is not set.
+//
+// https://git-scm.com/docs/git-config
+func (g *Commander) Config(ctx ExecContext, args ...string) (string, error) {
+	out, err := executewithOutput(ctx, "config", args...)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(out), nil
+}
@@ -178,6 +178,30 @@ func TestExecuteShortSymbolicRef(t *testing.T) {
 	})
 }
 
+func TestExecuteConfig(t *testing.T) {
+	t.Parallel()
+	cmd := git.NewCommander()
+
+	t.Run("shell cmd success returns trimmed value", func(t *testing.T) {
+		t.Parallel()
+
+		cmdCtx := getFakeCommand(t, "TestShellProcessSuccessSymbolicRef", "git config --get jira.profile")
+		value, err := cmd.Config(cmdCtx, "--get", "jira.profile")
+
+		require.NoError(t, err)
+		assert.Equal(t, "master", value)
+	})
+
+	t.Run("shell cmd failure returns err", func(t *testing.T) {
+		t.Parallel()
+
+		cmdCtx := getFakeCommand(t, "TestShellProcessFail", "git config --get jira.profile")
+		_, err := cmd.Config(cmdCtx, "--get", "jira.profile")
+
+		require.Error(t, err)
+	})
+}
+
 func TestShellProcessSuccess(_ *testing.T) {
 	if os.Getenv("GO_TEST_PROCESS") != "1" {
 		return
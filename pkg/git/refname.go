@@ -0,0 +1,167 @@
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// lockSuffix can not end a refname component, git uses it for lock files.
+const lockSuffix = ".lock"
+
+// invalidRefCharsRe matches the runs of characters that can not occur in a refname.
+var invalidRefCharsRe = regexp.MustCompile(`[\x00-\x20\x7f~^:?*\[\\]+`)
+
+// RefNameError is returned by ValidateRefName for a name that git does not accept.
+type RefNameError struct {
+	// Name is the invalid name.
+	Name string
+	// Part is the part of the name that breaks the rule.
+	Part string
+	// Reason describes the rule that is broken.
+	Reason string
+}
+
+func (e *RefNameError) Error() string {
+	if e.Part == "" || e.Part == e.Name {
+		return fmt.Sprintf("invalid branch name %q: %s", e.Name, e.Reason)
+	}
+
+	return fmt.Sprintf("invalid branch name %q: %s in %q", e.Name, e.Reason, e.Part)
+}
+
+// ValidateRefName checks that `name` is a valid branch name, following the rules of
+// `git check-ref-format --branch`. Returns a *RefNameError for an invalid name.
+//
+// https://git-scm.com/docs/git-check-ref-format
+func ValidateRefName(name string) error {
+	invalid := func(part, reason string) error {
+		return &RefNameError{Name: name, Part: part, Reason: reason}
+	}
+
+	switch {
+	case name == "":
+		return invalid("", "the name is empty")
+	case name == "@":
+		return invalid("", "the name can not be @")
+	case name == "HEAD":
+		return invalid("", "the name can not be HEAD")
+	case strings.HasPrefix(name, "-"):
+		return invalid("", "the name can not start with a dash")
+	case strings.HasPrefix(name, "/"), strings.HasSuffix(name, "/"):
+		return invalid("", "the name can not start or end with a slash")
+	case strings.HasSuffix(name, "."):
+		return invalid("", "the name can not end with a dot")
+	}
+
+	if loc := invalidRefCharsRe.FindStringIndex(name); loc != nil {
+		return invalid(surrounding(name, loc[0], loc[1]), describeInvalidChar(name[loc[0]]))
+	}
+
+	for _, seq := range []struct{ s, reason string }{
+		{"..", "the name can not contain two consecutive dots"},
+		{"@{", "the name can not contain @{"},
+		{"//", "the name can not contain consecutive slashes"},
+	} {
+		if i := strings.Index(name, seq.s); i >= 0 {
+			return invalid(surrounding(name, i, i+len(seq.s)), seq.reason)
+		}
+	}
+
+	for _, component := range strings.Split(name, "/") {
+		if strings.HasPrefix(component, ".") {
+			return invalid(component, "a component can not start with a dot")
+		}
+		if strings.HasSuffix(component, lockSuffix) {
+			return invalid(component, "a component can not end with "+lockSuffix)
+		}
+	}
+
+	return nil
+}
+
+// RepairRefName turns `name` into a valid branch name. Invalid characters are
+// replaced by dashes and the parts that can not occur in a refname, such as
+// consecutive dots, leading dashes and .lock suffixes, are removed. A valid
+// name is returned as is. Returns a *RefNameError when nothing valid is left.
+func RepairRefName(name string) (string, error) {
+	if ValidateRefName(name) == nil {
+		return name, nil
+	}
+
+	repaired := name
+	for {
+		next := repairRefNameOnce(repaired)
+		if next == repaired {
+			break
+		}
+		repaired = next
+	}
+
+	if err := ValidateRefName(repaired); err != nil {
+		return "", &RefNameError{Name: name, Part: name, Reason: "the name can not be repaired"}
+	}
+
+	return repaired, nil
+}
+
+func repairRefNameOnce(name string) string {
+	name = invalidRefCharsRe.ReplaceAllString(name, "-")
+	name = strings.ReplaceAll(name, "@{", "-")
+	for strings.Contains(name, "..") {
+		name = strings.ReplaceAll(name, "..", ".")
+	}
+
+	// Splitting on slashes and dropping empty components removes leading,
+	// trailing and consecutive slashes.
+	parts := strings.Split(name, "/")
+	kept := parts[:0]
+	for _, p := range parts {
+		p = strings.Trim(p, "-")
+		p = strings.TrimLeft(p, ".")
+		p = strings.TrimSuffix(p, lockSuffix)
+		if p != "" {
+			kept = append(kept, p)
+		}
+	}
+
+	name = strings.TrimRight(strings.Join(kept, "/"), ".")
+	if name == "@" || name == "HEAD" {
+		return ""
+	}
+
+	return name
+}
+
+// surrounding returns name[start:end] with the slash separated component
+// around it, to show where in the name a problem is.
+func surrounding(name string, start, end int) string {
+	if i := strings.LastIndex(name[:start], "/"); i >= 0 {
+		start = i + 1
+	} else {
+		start = 0
+	}
+
+	if i := strings.Index(name[end:], "/"); i >= 0 {
+		end += i
+	} else {
+		end = len(name)
+	}
+
+	if start >= end {
+		return name
+	}
+
+	return name[start:end]
+}
+
+func describeInvalidChar(c byte) string {
+	switch {
+	case c == ' ':
+		return "the name can not contain spaces"
+	case c < ' ' || c == 0x7f:
+		return "the name can not contain control characters"
+	default:
+		return fmt.Sprintf("the name can not contain %q", c)
+	}
+}
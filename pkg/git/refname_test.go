@@ -0,0 +1,113 @@
+package git_test
+
+import (
+	"testing"
+
+	"github.com/MaikelVeen/branch/pkg/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateRefName(t *testing.T) {
+	t.Parallel()
+
+	valid := []string{
+		"main",
+		"bug/ABC-1-fix-login",
+		"feature/v1.2",
+		"user@host",
+		"release/2024.1/hotfix",
+		"a-",
+		"ABC-1_fix",
+	}
+	for _, name := range valid {
+		require.NoError(t, git.ValidateRefName(name), name)
+	}
+
+	testCases := map[string]struct {
+		name string
+		part string
+	}{
+		"empty":                  {name: "", part: ""},
+		"at sign":                {name: "@", part: ""},
+		"HEAD":                   {name: "HEAD", part: ""},
+		"leading dash":           {name: "-ABC-1", part: ""},
+		"leading slash":          {name: "/ABC-1", part: ""},
+		"trailing slash":         {name: "bug/", part: ""},
+		"trailing dot":           {name: "ABC-1.", part: ""},
+		"space":                  {name: "user story/ABC-1", part: "user story"},
+		"tilde":                  {name: "bug/ABC~1", part: "ABC~1"},
+		"caret":                  {name: "bug/ABC^1", part: "ABC^1"},
+		"colon":                  {name: "bug:ABC-1/fix", part: "bug:ABC-1"},
+		"question mark":          {name: "bug/why?", part: "why?"},
+		"asterisk":               {name: "bug/*", part: "*"},
+		"open bracket":           {name: "[bug]/ABC-1", part: "[bug]"},
+		"backslash":              {name: `bug\ABC-1`, part: `bug\ABC-1`},
+		"control character":      {name: "bug/ABC\t1", part: "ABC\t1"},
+		"delete character":       {name: "bug/ABC\x7f1", part: "ABC\x7f1"},
+		"consecutive dots":       {name: "bug/ABC..1", part: "ABC..1"},
+		"at brace":               {name: "bug/ABC@{1}", part: "ABC@{1}"},
+		"consecutive slashes":    {name: "bug//ABC-1", part: "bug//ABC-1"},
+		"component with dot":     {name: "bug/.ABC-1", part: ".ABC-1"},
+		"component with .lock":   {name: "bug.lock/ABC-1", part: "bug.lock"},
+		"name ending with .lock": {name: "bug/ABC-1.lock", part: "ABC-1.lock"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := git.ValidateRefName(tc.name)
+
+			var refErr *git.RefNameError
+			require.ErrorAs(t, err, &refErr)
+			assert.Equal(t, tc.name, refErr.Name)
+			assert.Equal(t, tc.part, refErr.Part)
+			assert.NotEmpty(t, refErr.Reason)
+		})
+	}
+}
+
+func TestRefNameErrorShowsPart(t *testing.T) {
+	t.Parallel()
+
+	err := git.ValidateRefName("user story/ABC-1")
+	require.EqualError(t, err, `invalid branch name "user story/ABC-1": the name can not contain spaces in "user story"`)
+
+	err = git.ValidateRefName("-ABC-1")
+	require.EqualError(t, err, `invalid branch name "-ABC-1": the name can not start with a dash`)
+}
+
+func TestRepairRefName(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]string{
+		"bug/ABC-1-fix":       "bug/ABC-1-fix",
+		"user story/ABC-1":    "user-story/ABC-1",
+		"bug /ABC-1":          "bug/ABC-1",
+		"-bug/ABC-1":          "bug/ABC-1",
+		"bug//ABC-1/":         "bug/ABC-1",
+		"bug/ABC..1":          "bug/ABC.1",
+		"bug/.hidden":         "bug/hidden",
+		"bug.lock/ABC-1.lock": "bug/ABC-1",
+		"bug/ABC@{1}":         "bug/ABC-1}",
+		"bug/ABC-1.":          "bug/ABC-1",
+		"[bug/ABC~1":          "bug/ABC-1",
+		`bug\ABC-1`:           "bug-ABC-1",
+		"bug/ABC-1..lock":     "bug/ABC-1",
+	}
+
+	for input, want := range testCases {
+		got, err := git.RepairRefName(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, got, input)
+		require.NoError(t, git.ValidateRefName(got), input)
+	}
+
+	for _, input := range []string{"", "@", "HEAD", "//", "...", "-"} {
+		_, err := git.RepairRefName(input)
+
+		var refErr *git.RefNameError
+		require.ErrorAs(t, err, &refErr, input)
+	}
+}
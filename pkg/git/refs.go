@@ -0,0 +1,159 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// branchRefFormat is the `git for-each-ref` format parsed by ParseBranchRefs.
+	// Fields are separated by NUL characters, which can not occur in refnames.
+	branchRefFormat = "%(refname)%00%(refname:short)%00%(committerdate:iso-strict)%00%(upstream:short)"
+
+	localBranchesPattern  = "refs/heads"
+	remoteBranchesPattern = "refs/remotes"
+)
+
+// BranchRef is a branch as reported by `git for-each-ref`.
+type BranchRef struct {
+	// Ref is the full refname, such as refs/heads/main.
+	Ref string
+	// Name is the short name, such as main or origin/main.
+	Name string
+	// CommitDate is the committer date of the commit the branch points to.
+	CommitDate time.Time
+	// Upstream is the short name of the upstream branch, if any.
+	Upstream string
+}
+
+// Remote reports whether the branch is a remote-tracking branch.
+func (b BranchRef) Remote() bool {
+	return strings.HasPrefix(b.Ref, remoteBranchesPattern+"/")
+}
+
+// ForEachRef executes `git for-each-ref --format=<format> <patterns>` and
+// returns the output.
+//
+// https://git-scm.com/docs/git-for-each-ref
+func (g *Commander) ForEachRef(ctx ExecContext, format string, patterns ...string) (string, error) {
+	args := append([]string{"--format=" + format}, patterns...)
+	return executewithOutput(ctx, "for-each-ref", args...)
+}
+
+// LocalBranches returns the local branches of the repository.
+func (g *Commander) LocalBranches(ctx ExecContext) ([]BranchRef, error) {
+	out, err := g.ForEachRef(ctx, branchRefFormat, localBranchesPattern)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseBranchRefs(out)
+}
+
+// RemoteBranches returns the remote-tracking branches of the repository,
+// without the symbolic <remote>/HEAD refs.
+func (g *Commander) RemoteBranches(ctx ExecContext) ([]BranchRef, error) {
+	out, err := g.ForEachRef(ctx, branchRefFormat, remoteBranchesPattern)
+	if err != nil {
+		return nil, err
+	}
+
+	refs, err := ParseBranchRefs(out)
+	if err != nil {
+		return nil, err
+	}
+
+	branches := refs[:0]
+	for _, ref := range refs {
+		if !strings.HasSuffix(ref.Ref, "/HEAD") {
+			branches = append(branches, ref)
+		}
+	}
+
+	return branches, nil
+}
+
+// MergedBranches returns the short names of the branches whose tip is reachable
+// from `base`. When `remote` is true remote-tracking branches are returned.
+//
+// https://git-scm.com/docs/git-branch#Documentation/git-branch.txt---mergedltcommitgt
+func (g *Commander) MergedBranches(ctx ExecContext, base string, remote bool) ([]string, error) {
+	args := []string{"--format=%(refname:short)", "--merged", base}
+	if remote {
+		args = append([]string{"--remotes"}, args...)
+	}
+
+	out, err := g.Branch(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var branches []string
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			branches = append(branches, line)
+		}
+	}
+
+	return branches, nil
+}
+
+// BaseRef returns the ref to check whether branches are merged into `base`: the
+// local branch `base`, or the remote-tracking branch of `remote` when `base` only
+// exists on the remote, for example when branches are created from the remote.
+// Returns `base` when neither exists.
+func (g *Commander) BaseRef(ctx ExecContext, base, remote string) string {
+	if g.ShowRef(ctx, base) != nil && g.ShowRemoteRef(ctx, remote, base) == nil {
+		return remote + "/" + base
+	}
+
+	return base
+}
+
+// DeleteBranch executes `git branch -d <name>`. With `force` the branch is deleted
+// even when it is not merged (-D), with `remote` the remote-tracking branch is
+// deleted (-r), which does not delete the branch on the remote.
+//
+// https://git-scm.com/docs/git-branch#Documentation/git-branch.txt--d
+func (g *Commander) DeleteBranch(ctx ExecContext, name string, force, remote bool) error {
+	args := []string{"-d"}
+	if force {
+		args[0] = "-D"
+	}
+	if remote {
+		args = append(args, "-r")
+	}
+
+	_, err := g.Branch(ctx, append(args, name)...)
+	return err
+}
+
+// ParseBranchRefs parses the output of `git for-each-ref` in branchRefFormat.
+func ParseBranchRefs(out string) ([]BranchRef, error) {
+	var refs []BranchRef
+
+	for _, line := range strings.Split(out, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		fields := strings.Split(line, "\x00")
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("unexpected for-each-ref output %q", line)
+		}
+
+		ref := BranchRef{Ref: fields[0], Name: fields[1], Upstream: fields[3]}
+		if fields[2] != "" {
+			date, err := time.Parse(time.RFC3339, fields[2])
+			if err != nil {
+				return nil, fmt.Errorf("invalid commit date of %s: %w", ref.Name, err)
+			}
+			ref.CommitDate = date
+		}
+
+		refs = append(refs, ref)
+	}
+
+	return refs, nil
+}
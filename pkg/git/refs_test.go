@@ -0,0 +1,191 @@
+package git_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/MaikelVeen/branch/pkg/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const forEachRefCommand = "git for-each-ref " +
+	"--format=%(refname)%00%(refname:short)%00%(committerdate:iso-strict)%00%(upstream:short)"
+
+func TestParseBranchRefs(t *testing.T) {
+	t.Parallel()
+
+	out := "refs/heads/main\x00main\x002024-05-01T10:00:00+02:00\x00origin/main\n" +
+		"refs/heads/feature/ABC-1\x00feature/ABC-1\x002024-05-02T09:30:00Z\x00\n"
+
+	refs, err := git.ParseBranchRefs(out)
+	require.NoError(t, err)
+	require.Len(t, refs, 2)
+
+	assert.Equal(t, "main", refs[0].Name)
+	assert.Equal(t, "origin/main", refs[0].Upstream)
+	assert.True(t, refs[0].CommitDate.Equal(time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "feature/ABC-1", refs[1].Name)
+	assert.Empty(t, refs[1].Upstream)
+	assert.False(t, refs[1].Remote())
+
+	_, err = git.ParseBranchRefs("garbage\n")
+	require.Error(t, err)
+}
+
+func TestExecuteLocalBranches(t *testing.T) {
+	t.Parallel()
+	cmd := git.NewCommander()
+
+	t.Run("shell cmd success returns branches", func(t *testing.T) {
+		t.Parallel()
+
+		cmdCtx := getFakeCommand(t, "TestShellProcessSuccessForEachRef", forEachRefCommand+" refs/heads")
+		refs, err := cmd.LocalBranches(cmdCtx)
+
+		require.NoError(t, err)
+		require.Len(t, refs, 2)
+		assert.Equal(t, "feature/ABC-1", refs[1].Name)
+	})
+
+	t.Run("shell cmd failure returns err", func(t *testing.T) {
+		t.Parallel()
+
+		cmdCtx := getFakeCommand(t, "TestShellProcessFail", forEachRefCommand+" refs/heads")
+		_, err := cmd.LocalBranches(cmdCtx)
+
+		require.Error(t, err)
+	})
+}
+
+func TestExecuteRemoteBranches(t *testing.T) {
+	t.Parallel()
+	cmd := git.NewCommander()
+
+	cmdCtx := getFakeCommand(t, "TestShellProcessSuccessForEachRefRemote", forEachRefCommand+" refs/remotes")
+	refs, err := cmd.RemoteBranches(cmdCtx)
+
+	require.NoError(t, err)
+	require.Len(t, refs, 1)
+	assert.Equal(t, "origin/ABC-2", refs[0].Name)
+	assert.True(t, refs[0].Remote())
+}
+
+func TestExecuteBaseRef(t *testing.T) {
+	t.Parallel()
+	cmd := git.NewCommander()
+
+	const (
+		local  = "git show-ref --verify --quiet refs/heads/main"
+		remote = "git show-ref --verify --quiet refs/remotes/origin/main"
+	)
+
+	testCases := map[string]struct {
+		steps []fakeStep
+		want  string
+	}{
+		"local branch": {
+			steps: []fakeStep{{"TestShellProcessSuccess", local}},
+			want:  "main",
+		},
+		"only on the remote": {
+			steps: []fakeStep{{"TestShellProcessFail", local}, {"TestShellProcessSuccess", remote}},
+			want:  "origin/main",
+		},
+		"missing": {
+			steps: []fakeStep{{"TestShellProcessFail", local}, {"TestShellProcessFail", remote}},
+			want:  "main",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cmdCtx, done := getFakeCommands(t, tc.steps)
+			ref := cmd.BaseRef(cmdCtx, "main", "origin")
+			done()
+
+			assert.Equal(t, tc.want, ref)
+		})
+	}
+}
+
+func TestExecuteMergedBranches(t *testing.T) {
+	t.Parallel()
+	cmd := git.NewCommander()
+
+	t.Run("local branches", func(t *testing.T) {
+		t.Parallel()
+
+		cmdCtx := getFakeCommand(t, "TestShellProcessSuccessSymbolicRef",
+			"git branch --format=%(refname:short) --merged main")
+		merged, err := cmd.MergedBranches(cmdCtx, "main", false)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"master"}, merged)
+	})
+
+	t.Run("remote-tracking branches", func(t *testing.T) {
+		t.Parallel()
+
+		cmdCtx := getFakeCommand(t, "TestShellProcessSuccess",
+			"git branch --remotes --format=%(refname:short) --merged main")
+		merged, err := cmd.MergedBranches(cmdCtx, "main", true)
+
+		require.NoError(t, err)
+		assert.Empty(t, merged)
+	})
+}
+
+func TestShellProcessSuccessForEachRef(_ *testing.T) {
+	if os.Getenv("GO_TEST_PROCESS") != "1" {
+		return
+	}
+
+	fmt.Fprint(os.Stdout, "refs/heads/main\x00main\x002024-05-01T10:00:00+02:00\x00origin/main\n")
+	fmt.Fprint(os.Stdout, "refs/heads/feature/ABC-1\x00feature/ABC-1\x002024-05-02T09:30:00Z\x00\n")
+	os.Exit(0)
+}
+
+func TestShellProcessSuccessForEachRefRemote(_ *testing.T) {
+	if os.Getenv("GO_TEST_PROCESS") != "1" {
+		return
+	}
+
+	fmt.Fprint(os.Stdout, "refs/remotes/origin/HEAD\x00origin\x002024-05-01T10:00:00Z\x00\n")
+	fmt.Fprint(os.Stdout, "refs/remotes/origin/ABC-2\x00origin/ABC-2\x002024-05-01T10:00:00Z\x00\n")
+	os.Exit(0)
+}
+
+func TestExecuteDeleteBranch(t *testing.T) {
+	t.Parallel()
+	cmd := git.NewCommander()
+
+	testCases := map[string]struct {
+		force, remote bool
+		expect        string
+	}{
+		"merged branch":          {expect: "git branch -d ABC-1"},
+		"forced":                 {force: true, expect: "git branch -D ABC-1"},
+		"remote-tracking branch": {remote: true, expect: "git branch -d -r ABC-1"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cmdCtx := getFakeCommand(t, "TestShellProcessSuccess", tc.expect)
+			require.NoError(t, cmd.DeleteBranch(cmdCtx, "ABC-1", tc.force, tc.remote))
+		})
+	}
+
+	t.Run("shell cmd failure returns err", func(t *testing.T) {
+		t.Parallel()
+
+		cmdCtx := getFakeCommand(t, "TestShellProcessFail", "git branch -d ABC-1")
+		require.Error(t, cmd.DeleteBranch(cmdCtx, "ABC-1", false, false))
+	})
+}
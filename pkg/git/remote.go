@@ -0,0 +1,191 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DefaultRemote is the remote whose default branch new branches are based on.
+const DefaultRemote = "origin"
+
+// ErrNoDefaultBranch is returned when the default branch of a remote can not be determined.
+var ErrNoDefaultBranch = errors.New("could not determine the default branch")
+
+// RemoteHead returns the branch that refs/remotes/<remote>/HEAD points to, which
+// is set by `git clone` and `git remote set-head`.
+//
+// https://git-scm.com/docs/git-symbolic-ref
+func (g *Commander) RemoteHead(ctx ExecContext, remote string) (string, error) {
+	out, err := ctx("git", "symbolic-ref", "--quiet", "--short", "refs/remotes/"+remote+"/HEAD").Output()
+	if err != nil {
+		return "", err
+	}
+
+	branch, ok := strings.CutPrefix(strings.TrimSpace(string(out)), remote+"/")
+	if !ok || branch == "" {
+		return "", ErrNoDefaultBranch
+	}
+
+	return branch, nil
+}
+
+// LsRemoteHead asks `remote` which branch its HEAD points to, using
+// `git ls-remote --symref <remote> HEAD`. Git does not prompt for credentials.
+//
+// https://git-scm.com/docs/git-ls-remote
+func (g *Commander) LsRemoteHead(ctx ExecContext, remote string) (string, error) {
+	cmd := ctx("git", "ls-remote", "--symref", remote, "HEAD")
+	cmd.Env = append(cmd.Environ(), "GIT_TERMINAL_PROMPT=0")
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+
+	branch, ok := ParseSymref(string(out))
+	if !ok {
+		return "", ErrNoDefaultBranch
+	}
+
+	return branch, nil
+}
+
+// ParseSymref returns the branch HEAD points to in the output of
+// `git ls-remote --symref`, such as `ref: refs/heads/main	HEAD`.
+func ParseSymref(out string) (string, bool) {
+	for _, line := range strings.Split(out, "\n") {
+		symref, ok := strings.CutPrefix(line, "ref: ")
+		if !ok {
+			continue
+		}
+
+		ref, name, ok := strings.Cut(symref, "\t")
+		if !ok || strings.TrimSpace(name) != "HEAD" {
+			continue
+		}
+
+		if branch, ok := strings.CutPrefix(ref, localBranchesPattern+"/"); ok && branch != "" {
+			return branch, true
+		}
+	}
+
+	return "", false
+}
+
+// DefaultBranch returns the default branch of `remote`. It is read from
+// refs/remotes/<remote>/HEAD, and asked from the remote when that is not set.
+// Returns ErrNoDefaultBranch when neither works, for example without a remote.
+func (g *Commander) DefaultBranch(ctx ExecContext, remote string) (string, error) {
+	if branch, err := g.RemoteHead(ctx, remote); err == nil {
+		return branch, nil
+	}
+
+	if branch, err := g.LsRemoteHead(ctx, remote); err == nil {
+		return branch, nil
+	}
+
+	return "", fmt.Errorf("%w of %s", ErrNoDefaultBranch, remote)
+}
+
+// Fetch executes `git fetch <remote> <refspecs>`, which also updates the
+// remote-tracking branches of the fetched branches.
+//
+// https://git-scm.com/docs/git-fetch
+func (g *Commander) Fetch(ctx ExecContext, remote string, refspecs ...string) error {
+	_, err := executewithOutput(ctx, "fetch", append([]string{"--quiet", remote}, refspecs...)...)
+	return err
+}
+
+// ShowRemoteRef checks whether the remote-tracking branch `b` of `remote` exists.
+// Returns an error when it does not.
+//
+// https://git-scm.com/docs/git-show-ref
+func (g *Commander) ShowRemoteRef(ctx ExecContext, remote, b string) error {
+	pattern := fmt.Sprintf("%s/%s/%s", remoteBranchesPattern, remote, b)
+	cmd := ctx("git", "show-ref", "--verify", "--quiet", pattern)
+	return cmd.Run()
+}
+
+// TrackingBranch creates branch `b` from `upstream`, such as origin/main, with
+// `upstream` as its upstream branch, without checking it out.
+//
+// https://git-scm.com/docs/git-branch#Documentation/git-branch.txt---track
+func (g *Commander) TrackingBranch(ctx ExecContext, b, upstream string) error {
+	_, err := g.Branch(ctx, "--track", b, upstream)
+	return err
+}
+
+// IsBranchPattern reports whether `name` is a glob pattern, such as release/*.
+func IsBranchPattern(name string) bool {
+	return strings.ContainsAny(name, "*?[")
+}
+
+// LatestBranch returns the local or remote-tracking branch of `remote` that matches
+// the glob `pattern` and has the highest version, such as release/2.10 for release/*.
+// Remote-tracking branches are returned without the name of the remote.
+func (g *Commander) LatestBranch(ctx ExecContext, pattern, remote string) (string, error) {
+	remotePrefix := remoteBranchesPattern + "/" + remote + "/"
+
+	out, err := g.ForEachRef(ctx, "%(refname)", localBranchesPattern+"/"+pattern, remotePrefix+pattern)
+	if err != nil {
+		return "", err
+	}
+
+	var branches []string
+	for _, ref := range strings.Split(out, "\n") {
+		ref = strings.TrimSpace(ref)
+		if name, ok := strings.CutPrefix(ref, localBranchesPattern+"/"); ok {
+			branches = append(branches, name)
+		} else if name, ok := strings.CutPrefix(ref, remotePrefix); ok && name != "HEAD" {
+			branches = append(branches, name)
+		}
+	}
+
+	if len(branches) == 0 {
+		return "", fmt.Errorf("no branch matches %s", pattern)
+	}
+
+	return slices.MaxFunc(branches, CompareVersions), nil
+}
+
+// CompareVersions compares `a` and `b` like strings, except that runs of digits
+// are compared as numbers, so release/2.10 sorts after release/2.9.
+func CompareVersions(a, b string) int {
+	for a != "" && b != "" {
+		var x, y string
+		x, a = nextVersionPart(a)
+		y, b = nextVersionPart(b)
+
+		if isDigit(x[0]) && isDigit(y[0]) {
+			// Leading zeros do not change the number.
+			x, y = strings.TrimLeft(x, "0"), strings.TrimLeft(y, "0")
+			if len(x) != len(y) {
+				return len(x) - len(y)
+			}
+		}
+
+		if c := strings.Compare(x, y); c != 0 {
+			return c
+		}
+	}
+
+	return len(a) - len(b)
+}
+
+// nextVersionPart splits the leading run of digits or non-digits off `s`.
+func nextVersionPart(s string) (string, string) {
+	digit := isDigit(s[0])
+
+	i := 1
+	for i < len(s) && isDigit(s[i]) == digit {
+		i++
+	}
+
+	return s[:i], s[i:]
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
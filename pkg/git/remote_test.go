@@ -0,0 +1,216 @@
+package git_test
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/MaikelVeen/branch/pkg/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSymref(t *testing.T) {
+	t.Parallel()
+
+	out := "ref: refs/heads/develop\tHEAD\n" +
+		"3f2a1c9e0b8d7a6f5e4d3c2b1a0f9e8d7c6b5a49\tHEAD\n"
+
+	branch, ok := git.ParseSymref(out)
+	require.True(t, ok)
+	assert.Equal(t, "develop", branch)
+
+	_, ok = git.ParseSymref("3f2a1c9e0b8d7a6f5e4d3c2b1a0f9e8d7c6b5a49\tHEAD\n")
+	assert.False(t, ok, "without a symref the branch is unknown")
+
+	_, ok = git.ParseSymref("")
+	assert.False(t, ok)
+}
+
+func TestExecuteRemoteHead(t *testing.T) {
+	t.Parallel()
+	cmd := git.NewCommander()
+	exp := "git symbolic-ref --quiet --short refs/remotes/origin/HEAD"
+
+	t.Run("shell cmd success returns branch without remote", func(t *testing.T) {
+		t.Parallel()
+
+		cmdCtx := getFakeCommand(t, "TestShellProcessSuccessRemoteHead", exp)
+		branch, err := cmd.RemoteHead(cmdCtx, git.DefaultRemote)
+
+		require.NoError(t, err)
+		assert.Equal(t, "trunk", branch)
+	})
+
+	t.Run("shell cmd failure returns err", func(t *testing.T) {
+		t.Parallel()
+
+		cmdCtx := getFakeCommand(t, "TestShellProcessFail", exp)
+		_, err := cmd.RemoteHead(cmdCtx, git.DefaultRemote)
+
+		require.Error(t, err)
+	})
+}
+
+func TestExecuteLsRemoteHead(t *testing.T) {
+	t.Parallel()
+	cmd := git.NewCommander()
+	exp := "git ls-remote --symref origin HEAD"
+
+	t.Run("shell cmd success returns branch", func(t *testing.T) {
+		t.Parallel()
+
+		cmdCtx := getFakeCommand(t, "TestShellProcessSuccessLsRemote", exp)
+		branch, err := cmd.LsRemoteHead(cmdCtx, git.DefaultRemote)
+
+		require.NoError(t, err)
+		assert.Equal(t, "master", branch)
+	})
+
+	t.Run("shell cmd failure returns err", func(t *testing.T) {
+		t.Parallel()
+
+		cmdCtx := getFakeCommand(t, "TestShellProcessFail", exp)
+		_, err := cmd.LsRemoteHead(cmdCtx, git.DefaultRemote)
+
+		require.Error(t, err)
+	})
+}
+
+func TestExecuteLatestBranch(t *testing.T) {
+	t.Parallel()
+	cmd := git.NewCommander()
+	exp := "git for-each-ref --format=%(refname) refs/heads/release/* refs/remotes/origin/release/*"
+
+	t.Run("shell cmd success returns highest version", func(t *testing.T) {
+		t.Parallel()
+
+		cmdCtx := getFakeCommand(t, "TestShellProcessSuccessReleaseRefs", exp)
+		branch, err := cmd.LatestBranch(cmdCtx, "release/*", git.DefaultRemote)
+
+		require.NoError(t, err)
+		assert.Equal(t, "release/2.10", branch)
+	})
+
+	t.Run("no matching branch returns err", func(t *testing.T) {
+		t.Parallel()
+
+		cmdCtx := getFakeCommand(t, "TestShellProcessSuccess", exp)
+		_, err := cmd.LatestBranch(cmdCtx, "release/*", git.DefaultRemote)
+
+		require.ErrorContains(t, err, "no branch matches release/*")
+	})
+}
+
+func TestExecuteFetch(t *testing.T) {
+	t.Parallel()
+	cmd := git.NewCommander()
+	exp := "git fetch --quiet origin main"
+
+	t.Run("shell cmd success returns no err", func(t *testing.T) {
+		t.Parallel()
+
+		cmdCtx := getFakeCommand(t, "TestShellProcessSuccess", exp)
+		require.NoError(t, cmd.Fetch(cmdCtx, git.DefaultRemote, "main"))
+	})
+
+	t.Run("shell cmd failure returns err", func(t *testing.T) {
+		t.Parallel()
+
+		cmdCtx := getFakeCommand(t, "TestShellProcessFail", exp)
+		require.Error(t, cmd.Fetch(cmdCtx, git.DefaultRemote, "main"))
+	})
+}
+
+func TestExecuteShowRemoteRef(t *testing.T) {
+	t.Parallel()
+	cmd := git.NewCommander()
+	exp := "git show-ref --verify --quiet refs/remotes/origin/develop"
+
+	t.Run("shell cmd success returns no err", func(t *testing.T) {
+		t.Parallel()
+
+		cmdCtx := getFakeCommand(t, "TestShellProcessSuccess", exp)
+		require.NoError(t, cmd.ShowRemoteRef(cmdCtx, git.DefaultRemote, "develop"))
+	})
+
+	t.Run("shell cmd failure returns err", func(t *testing.T) {
+		t.Parallel()
+
+		cmdCtx := getFakeCommand(t, "TestShellProcessFail", exp)
+		require.Error(t, cmd.ShowRemoteRef(cmdCtx, git.DefaultRemote, "develop"))
+	})
+}
+
+func TestExecuteTrackingBranch(t *testing.T) {
+	t.Parallel()
+	cmd := git.NewCommander()
+	exp := "git branch --track feature/ABC-1 origin/main"
+
+	t.Run("shell cmd success returns no err", func(t *testing.T) {
+		t.Parallel()
+
+		cmdCtx := getFakeCommand(t, "TestShellProcessSuccess", exp)
+		require.NoError(t, cmd.TrackingBranch(cmdCtx, "feature/ABC-1", "origin/main"))
+	})
+
+	t.Run("shell cmd failure returns err", func(t *testing.T) {
+		t.Parallel()
+
+		cmdCtx := getFakeCommand(t, "TestShellProcessFail", exp)
+		require.Error(t, cmd.TrackingBranch(cmdCtx, "feature/ABC-1", "origin/main"))
+	})
+}
+
+func TestCompareVersions(t *testing.T) {
+	t.Parallel()
+
+	branches := []string{"release/2.10", "release/2.9", "release/10.0", "release/2.9.1", "release/1.0", "release/02.11"}
+	slices.SortFunc(branches, git.CompareVersions)
+
+	assert.Equal(t, []string{
+		"release/1.0", "release/2.9", "release/2.9.1", "release/2.10", "release/02.11", "release/10.0",
+	}, branches)
+
+	assert.Zero(t, git.CompareVersions("release/1.0", "release/1.0"))
+	assert.Negative(t, git.CompareVersions("release", "release/1"))
+	assert.Positive(t, git.CompareVersions("release-b", "release-a"))
+}
+
+func TestIsBranchPattern(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, git.IsBranchPattern("release/*"))
+	assert.True(t, git.IsBranchPattern("release/v[0-9]"))
+	assert.False(t, git.IsBranchPattern("develop"))
+}
+
+func TestShellProcessSuccessRemoteHead(_ *testing.T) {
+	if os.Getenv("GO_TEST_PROCESS") != "1" {
+		return
+	}
+
+	fmt.Fprintln(os.Stdout, "origin/trunk")
+	os.Exit(0)
+}
+
+func TestShellProcessSuccessLsRemote(_ *testing.T) {
+	if os.Getenv("GO_TEST_PROCESS") != "1" {
+		return
+	}
+
+	fmt.Fprint(os.Stdout, "ref: refs/heads/master\tHEAD\n3f2a1c9e0b8d7a6f5e4d3c2b1a0f9e8d7c6b5a49\tHEAD\n")
+	os.Exit(0)
+}
+
+func TestShellProcessSuccessReleaseRefs(_ *testing.T) {
+	if os.Getenv("GO_TEST_PROCESS") != "1" {
+		return
+	}
+
+	fmt.Fprint(os.Stdout, "refs/heads/release/2.9\n"+
+		"refs/remotes/origin/release/2.10\n"+
+		"refs/remotes/origin/release/1.4\n")
+	os.Exit(0)
+}
@@ -0,0 +1,70 @@
+package git
+
+import (
+	"strings"
+)
+
+// stashRef is the ref that points to the latest stash.
+const stashRef = "refs/stash"
+
+// StashPush executes `git stash push --include-untracked --message <message>`
+// and returns the commit of the new stash. Returns an empty string when there
+// were no changes to stash.
+//
+// https://git-scm.com/docs/git-stash#Documentation/git-stash.txt-push
+func (g *Commander) StashPush(ctx ExecContext, message string) (string, error) {
+	// A failure means there is no stash yet.
+	before, _ := g.RevParse(ctx, stashRef)
+
+	if _, err := executewithOutput(ctx, "stash", "push", "--include-untracked", "--message", message); err != nil {
+		return "", err
+	}
+
+	after, err := g.RevParse(ctx, stashRef)
+	if err != nil || after == before {
+		return "", nil //nolint:nilerr // Without a new stash there were no changes to stash.
+	}
+
+	return after, nil
+}
+
+// StashPop executes `git stash pop`, which applies the latest stash and drops it.
+// When applying fails, for example because of conflicts, the stash is kept.
+//
+// https://git-scm.com/docs/git-stash#Documentation/git-stash.txt-pop
+func (g *Commander) StashPop(ctx ExecContext) error {
+	_, err := executewithOutput(ctx, "stash", "pop", "--quiet")
+	return err
+}
+
+// RevParse executes `git rev-parse --verify --quiet <rev>` and returns the
+// commit `rev` points to.
+//
+// https://git-scm.com/docs/git-rev-parse
+func (g *Commander) RevParse(ctx ExecContext, rev string) (string, error) {
+	out, err := executewithOutput(ctx, "rev-parse", "--verify", "--quiet", rev)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(out), nil
+}
+
+// UnmergedFiles returns the files with unresolved conflicts.
+//
+// https://git-scm.com/docs/git-diff#Documentation/git-diff.txt---diff-filter
+func (g *Commander) UnmergedFiles(ctx ExecContext) ([]string, error) {
+	out, err := executewithOutput(ctx, "diff", "--name-only", "--diff-filter=U")
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+
+	return files, nil
+}
@@ -0,0 +1,207 @@
+package git_test
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/MaikelVeen/branch/pkg/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteStashPush(t *testing.T) {
+	t.Parallel()
+	cmd := git.NewCommander()
+
+	const (
+		revParse = "git rev-parse --verify --quiet refs/stash"
+		push     = "git stash push --include-untracked --message branch: test"
+	)
+
+	testCases := map[string]struct {
+		steps   []fakeStep
+		want    string
+		wantErr bool
+	}{
+		"new stash": {
+			steps: []fakeStep{
+				{"TestShellProcessSuccessRevParse", revParse},
+				{"TestShellProcessSuccess", push},
+				{"TestShellProcessSuccessRevParseNewStash", revParse},
+			},
+			want: "9e8d7c6b5a493f2a1c9e0b8d7a6f5e4d3c2b1a0f",
+		},
+		"nothing to stash": {
+			steps: []fakeStep{
+				{"TestShellProcessSuccessRevParse", revParse},
+				{"TestShellProcessSuccess", push},
+				{"TestShellProcessSuccessRevParse", revParse},
+			},
+			want: "",
+		},
+		"no previous stash": {
+			steps: []fakeStep{
+				{"TestShellProcessFail", revParse},
+				{"TestShellProcessSuccess", push},
+				{"TestShellProcessSuccessRevParseNewStash", revParse},
+			},
+			want: "9e8d7c6b5a493f2a1c9e0b8d7a6f5e4d3c2b1a0f",
+		},
+		"nothing to stash without previous stash": {
+			steps: []fakeStep{
+				{"TestShellProcessFail", revParse},
+				{"TestShellProcessSuccess", push},
+				{"TestShellProcessFail", revParse},
+			},
+			want: "",
+		},
+		"push failure": {
+			steps: []fakeStep{
+				{"TestShellProcessSuccessRevParse", revParse},
+				{"TestShellProcessFail", push},
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cmdCtx, done := getFakeCommands(t, tc.steps)
+			stash, err := cmd.StashPush(cmdCtx, "branch: test")
+			done()
+
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, stash)
+		})
+	}
+}
+
+func TestExecuteStashPop(t *testing.T) {
+	t.Parallel()
+	cmd := git.NewCommander()
+
+	t.Run("shell cmd success returns no err", func(t *testing.T) {
+		t.Parallel()
+
+		cmdCtx := getFakeCommand(t, "TestShellProcessSuccess", "git stash pop --quiet")
+		require.NoError(t, cmd.StashPop(cmdCtx))
+	})
+
+	t.Run("shell cmd failure returns err", func(t *testing.T) {
+		t.Parallel()
+
+		cmdCtx := getFakeCommand(t, "TestShellProcessFail", "git stash pop --quiet")
+		require.Error(t, cmd.StashPop(cmdCtx))
+	})
+}
+
+func TestExecuteRevParse(t *testing.T) {
+	t.Parallel()
+	cmd := git.NewCommander()
+
+	t.Run("shell cmd success returns trimmed commit", func(t *testing.T) {
+		t.Parallel()
+
+		cmdCtx := getFakeCommand(t, "TestShellProcessSuccessRevParse", "git rev-parse --verify --quiet refs/stash")
+		commit, err := cmd.RevParse(cmdCtx, "refs/stash")
+
+		require.NoError(t, err)
+		assert.Equal(t, "3f2a1c9e0b8d7a6f5e4d3c2b1a0f9e8d7c6b5a49", commit)
+	})
+
+	t.Run("shell cmd failure returns err", func(t *testing.T) {
+		t.Parallel()
+
+		cmdCtx := getFakeCommand(t, "TestShellProcessFail", "git rev-parse --verify --quiet refs/stash")
+		_, err := cmd.RevParse(cmdCtx, "refs/stash")
+
+		require.Error(t, err)
+	})
+}
+
+func TestExecuteUnmergedFiles(t *testing.T) {
+	t.Parallel()
+	cmd := git.NewCommander()
+	exp := "git diff --name-only --diff-filter=U"
+
+	t.Run("shell cmd success returns files", func(t *testing.T) {
+		t.Parallel()
+
+		cmdCtx := getFakeCommand(t, "TestShellProcessSuccessUnmergedFiles", exp)
+		files, err := cmd.UnmergedFiles(cmdCtx)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"go.mod", "pkg/cmd/create.go"}, files)
+	})
+
+	t.Run("shell cmd success without conflicts returns no files", func(t *testing.T) {
+		t.Parallel()
+
+		cmdCtx := getFakeCommand(t, "TestShellProcessSuccess", exp)
+		files, err := cmd.UnmergedFiles(cmdCtx)
+
+		require.NoError(t, err)
+		assert.Empty(t, files)
+	})
+}
+
+func TestShellProcessSuccessRevParse(_ *testing.T) {
+	if os.Getenv("GO_TEST_PROCESS") != "1" {
+		return
+	}
+
+	fmt.Fprintln(os.Stdout, "3f2a1c9e0b8d7a6f5e4d3c2b1a0f9e8d7c6b5a49")
+	os.Exit(0)
+}
+
+func TestShellProcessSuccessUnmergedFiles(_ *testing.T) {
+	if os.Getenv("GO_TEST_PROCESS") != "1" {
+		return
+	}
+
+	fmt.Fprint(os.Stdout, "go.mod\npkg/cmd/create.go\n")
+	os.Exit(0)
+}
+
+func TestShellProcessSuccessRevParseNewStash(_ *testing.T) {
+	if os.Getenv("GO_TEST_PROCESS") != "1" {
+		return
+	}
+
+	fmt.Fprintln(os.Stdout, "9e8d7c6b5a493f2a1c9e0b8d7a6f5e4d3c2b1a0f")
+	os.Exit(0)
+}
+
+// fakeStep is a command expected by getFakeCommands and the shell substitute that runs it.
+type fakeStep struct {
+	shellSub        string
+	expectedCommand string
+}
+
+// getFakeCommands works like getFakeCommand for a sequence of commands: the nth
+// command is checked against and run by the nth step. The returned function checks
+// that all steps are run.
+func getFakeCommands(t *testing.T, steps []fakeStep) (git.ExecContext, func()) {
+	var i int
+
+	ctx := func(command string, args ...string) *exec.Cmd {
+		require.Less(t, i, len(steps), "unexpected command %s %s", command, strings.Join(args, " "))
+		step := steps[i]
+		i++
+
+		return getFakeCommand(t, step.shellSub, step.expectedCommand)(command, args...)
+	}
+
+	return ctx, func() {
+		assert.Len(t, steps, i, "all commands are run")
+	}
+}
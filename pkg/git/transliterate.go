@@ -0,0 +1,78 @@
+package git
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// transliterations maps lower case letters that do not decompose into an ASCII
+// base letter and a diacritic to their ASCII transliteration.
+var transliterations = map[rune]string{
+	// Latin letters without a decomposition.
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'ł': "l", 'đ': "d", 'ð': "d", 'þ': "th",
+	'ı': "i", 'ħ': "h", 'ŋ': "ng", 'ſ': "s", 'ŧ': "t", 'ĸ': "k", 'ŀ': "l", 'ĳ': "ij",
+
+	// Cyrillic, including the Ukrainian, Belarusian, Serbian and Macedonian letters.
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya", 'є': "ye", 'і': "i", 'ї': "yi", 'ґ': "g", 'ў': "u", 'ђ': "dj", 'ј': "j",
+	'љ': "lj", 'њ': "nj", 'ћ': "c", 'џ': "dz", 'ѓ': "gj", 'ќ': "kj", 'ѕ': "dz",
+
+	// Greek, accented letters are folded to these first.
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i", 'θ': "th",
+	'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x", 'ο': "o", 'π': "p",
+	'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y", 'φ': "f", 'χ': "ch", 'ψ': "ps",
+	'ω': "o",
+}
+
+// foldDiacritics decomposes characters and removes the combining marks,
+// turning for example é into e.
+var foldDiacritics = transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+
+// Transliterate replaces the Latin letters with diacritics and the Cyrillic and
+// Greek letters in `s` by ASCII letters. Other characters, such as CJK, are kept.
+func Transliterate(s string) string {
+	// Letters such as the Cyrillic й decompose into a base letter and a mark,
+	// so they are mapped before folding. Accented Greek letters are mapped after.
+	s = mapTransliterations(norm.NFC.String(s))
+
+	folded, _, err := transform.String(foldDiacritics, s)
+	if err != nil {
+		return s
+	}
+
+	return mapTransliterations(folded)
+}
+
+func mapTransliterations(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+
+	for _, r := range s {
+		if r < unicode.MaxASCII {
+			b.WriteRune(r)
+			continue
+		}
+
+		lower := unicode.ToLower(r)
+		t, ok := transliterations[lower]
+		if !ok {
+			b.WriteRune(r)
+			continue
+		}
+
+		// Keep the case, Ж becomes Zh.
+		if lower != r && t != "" {
+			t = strings.ToUpper(t[:1]) + t[1:]
+		}
+		b.WriteString(t)
+	}
+
+	return b.String()
+}
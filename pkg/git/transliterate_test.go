@@ -0,0 +1,79 @@
+package git_test
+
+import (
+	"testing"
+
+	"github.com/MaikelVeen/branch/pkg/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransliterate(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]string{
+		"Überprüfung der Zahlungsmethode": "Uberprufung der Zahlungsmethode",
+		"Café façade":                     "Cafe facade",
+		"Straße":                          "Strasse",
+		"Łódź Ærø":                        "Lodz Aero",
+		"Исправить ошибку входа": "Ispravit oshibku vkhoda",
+		"Щука й ЖУК":             "Shchuka y ZhUK",
+		"Їжак":                   "Yizhak",
+		"Διόρθωση σφάλματος":     "Diorthosi sfalmatos",
+		"修复登录":                   "修复登录",
+		"plain ascii":            "plain ascii",
+	}
+
+	for input, want := range testCases {
+		assert.Equal(t, want, git.Transliterate(input), input)
+	}
+}
+
+func TestSanitizeTransliterates(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]string{
+		"Überprüfung der Zahlungsmethode": "uberprufung-der-zahlungsmethode",
+		"Café façade":                     "cafe-facade",
+		"Исправить ошибку входа":          "ispravit-oshibku-vkhoda",
+		"修复登录":                            "",
+		"修复 login 问题":                     "login",
+	}
+
+	for input, want := range testCases {
+		assert.Equal(t, want, git.FormatAsValidRef(input), input)
+	}
+}
+
+func TestSanitizeFallback(t *testing.T) {
+	t.Parallel()
+
+	opts := git.DefaultSanitizeOptions()
+	opts.Fallback = "Update Work"
+
+	got, err := git.Sanitize("修复登录", opts)
+	require.NoError(t, err)
+	assert.Equal(t, "update-work", got)
+
+	got, err = git.Sanitize("Fix login", opts)
+	require.NoError(t, err)
+	assert.Equal(t, "fix-login", got)
+}
+
+func TestTrimDanglingSeparators(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]string{
+		"bug/ABC-1-":     "bug/ABC-1",
+		"bug/ABC-1_":     "bug/ABC-1",
+		"bug/":           "bug",
+		"/ABC-1":         "ABC-1",
+		"feature/-ABC-1": "feature/ABC-1",
+		"a//b":           "a/b",
+		"bug/ABC-1":      "bug/ABC-1",
+	}
+
+	for input, want := range testCases {
+		assert.Equal(t, want, git.TrimDanglingSeparators(input), input)
+	}
+}
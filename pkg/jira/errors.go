@@ -0,0 +1,133 @@
+package jira
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxErrorBodySize caps the amount of an error response body that is read.
+const maxErrorBodySize = 1 << 20
+
+// ErrorResponse is returned by Client.Do when the Jira API responds with
+// a status code of 400 or higher.
+//
+// https://developer.atlassian.com/cloud/jira/platform/rest/v3/intro/#status-codes
+type ErrorResponse struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int `json:"-"`
+	// Method and URL identify the request that failed.
+	Method string `json:"-"`
+	URL    string `json:"-"`
+	// RetryAfter is the parsed Retry-After header, zero if absent.
+	RetryAfter time.Duration `json:"-"`
+
+	// ErrorMessages contains the general error messages.
+	ErrorMessages []string `json:"errorMessages"`
+	// Errors maps field names to field specific error messages.
+	Errors map[string]string `json:"errors"`
+}
+
+func (e *ErrorResponse) Error() string {
+	msgs := append([]string{}, e.ErrorMessages...)
+
+	fields := make([]string, 0, len(e.Errors))
+	for field := range e.Errors {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", field, e.Errors[field]))
+	}
+
+	detail := http.StatusText(e.StatusCode)
+	if len(msgs) > 0 {
+		detail = strings.Join(msgs, "; ")
+	}
+
+	return fmt.Sprintf("%s %s: %d %s", e.Method, e.URL, e.StatusCode, detail)
+}
+
+// newErrorResponse builds an ErrorResponse from `resp`. The body is decoded
+// when it contains the standard Jira error collection, otherwise it is ignored.
+func newErrorResponse(resp *http.Response) *ErrorResponse {
+	e := &ErrorResponse{
+		StatusCode: resp.StatusCode,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+	}
+
+	if resp.Request != nil {
+		e.Method = resp.Request.Method
+		if resp.Request.URL != nil {
+			e.URL = resp.Request.URL.Redacted()
+		}
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	if err == nil && len(data) > 0 {
+		_ = json.Unmarshal(data, e)
+	}
+
+	return e
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is
+// either a number of seconds or an HTTP date.
+func parseRetryAfter(v string, now time.Time) time.Duration {
+	if v == "" {
+		return 0
+	}
+
+	if secs, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+
+	if t, err := http.ParseTime(v); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+
+	return 0
+}
+
+// HasStatus reports whether `err` is an *ErrorResponse with the given status code.
+func HasStatus(err error, code int) bool {
+	var e *ErrorResponse
+	if errors.As(err, &e) {
+		return e.StatusCode == code
+	}
+
+	return false
+}
+
+// IsNotFound reports whether `err` is a 404 response, which Jira also returns
+// when the issue exists but the user is not allowed to see it.
+func IsNotFound(err error) bool {
+	return HasStatus(err, http.StatusNotFound)
+}
+
+// IsUnauthorized reports whether `err` is a 401 response, meaning the
+// credentials are missing, invalid or expired.
+func IsUnauthorized(err error) bool {
+	return HasStatus(err, http.StatusUnauthorized)
+}
+
+// IsForbidden reports whether `err` is a 403 response, meaning the user
+// lacks the permission for the operation.
+func IsForbidden(err error) bool {
+	return HasStatus(err, http.StatusForbidden)
+}
+
+// IsRateLimited reports whether `err` is a 429 response.
+func IsRateLimited(err error) bool {
+	return HasStatus(err, http.StatusTooManyRequests)
+}
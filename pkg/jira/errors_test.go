@@ -0,0 +1,103 @@
+package jira_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/MaikelVeen/branch/pkg/jira"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorResponse(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		status  int
+		header  map[string]string
+		body    string
+		check   func(error) bool
+		message string
+	}{
+		"not found with error messages": {
+			status:  http.StatusNotFound,
+			body:    `{"errorMessages":["Issue does not exist or you do not have permission to see it."],"errors":{}}`,
+			check:   jira.IsNotFound,
+			message: "GET %s/rest/api/3/issue/TEST-1: 404 Issue does not exist or you do not have permission to see it.",
+		},
+		"unauthorized without body": {
+			status:  http.StatusUnauthorized,
+			check:   jira.IsUnauthorized,
+			message: "GET %s/rest/api/3/issue/TEST-1: 401 Unauthorized",
+		},
+		"field errors are sorted": {
+			status:  http.StatusBadRequest,
+			body:    `{"errorMessages":[],"errors":{"summary":"required","assignee":"invalid"}}`,
+			check:   func(err error) bool { return jira.HasStatus(err, http.StatusBadRequest) },
+			message: "GET %s/rest/api/3/issue/TEST-1: 400 assignee: invalid; summary: required",
+		},
+		"non json body is ignored": {
+			status:  http.StatusForbidden,
+			body:    `<html>forbidden</html>`,
+			check:   jira.IsForbidden,
+			message: "GET %s/rest/api/3/issue/TEST-1: 403 Forbidden",
+		},
+		"rate limited": {
+			status:  http.StatusTooManyRequests,
+			header:  map[string]string{"Retry-After": "0"},
+			check:   jira.IsRateLimited,
+			message: "GET %s/rest/api/3/issue/TEST-1: 429 Too Many Requests",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				for k, v := range tc.header {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			t.Cleanup(srv.Close)
+
+			client, err := jira.NewClient(srv.URL)
+			require.NoError(t, err)
+
+			_, err = client.Issue.GetIssue(context.Background(), "TEST-1")
+			require.Error(t, err)
+			assert.True(t, tc.check(err))
+			assert.EqualError(t, err, fmt.Sprintf(tc.message, srv.URL))
+
+			var apiErr *jira.ErrorResponse
+			require.ErrorAs(t, err, &apiErr)
+			assert.Equal(t, tc.status, apiErr.StatusCode)
+			assert.Equal(t, http.MethodGet, apiErr.Method)
+		})
+	}
+
+	t.Run("retry after is parsed", func(t *testing.T) {
+		t.Parallel()
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		t.Cleanup(srv.Close)
+
+		client, err := jira.NewClient(srv.URL)
+		require.NoError(t, err)
+
+		_, err = client.Myself.Myself(context.Background())
+
+		var apiErr *jira.ErrorResponse
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, 30*time.Second, apiErr.RetryAfter)
+	})
+}
@@ -0,0 +1,262 @@
+package jira
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// customFieldPrefix is the prefix of the ids of custom fields.
+	customFieldPrefix = "customfield_"
+
+	// SprintFieldName is the name of the custom field holding the sprints of an issue.
+	SprintFieldName = "Sprint"
+	// EpicLinkFieldName is the name of the custom field linking an issue to its
+	// epic in Jira Server and Data Center, and in older Jira Cloud projects.
+	EpicLinkFieldName = "Epic Link"
+
+	// EpicIssueTypeName is the name of the epic issue type.
+	EpicIssueTypeName = "Epic"
+	// epicHierarchyLevel is the hierarchy level of epics, also when they are renamed.
+	epicHierarchyLevel = 1
+
+	sprintStateActive = "active"
+)
+
+// These match the name and state in the string representation of a sprint returned
+// by older versions of Jira Server, such as
+// com.atlassian.greenhopper.service.sprint.Sprint@1[id=1,state=ACTIVE,name=Sprint 1,...].
+var (
+	legacySprintNameRe  = regexp.MustCompile(`[\[,]name=([^,\]]*)`)
+	legacySprintStateRe = regexp.MustCompile(`[\[,]state=([^,\]]*)`)
+)
+
+// Parent is the parent of an issue, such as the epic of a story or the story of a sub-task.
+type Parent struct {
+	ID     string       `json:"id"`
+	Key    string       `json:"key"`
+	Fields ParentFields `json:"fields"`
+}
+
+type ParentFields struct {
+	Summary   string    `json:"summary"`
+	Status    Status    `json:"status"`
+	Issuetype IssueType `json:"issuetype"`
+}
+
+type Priority struct {
+	Self string `json:"self"`
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type Component struct {
+	Self string `json:"self"`
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type Version struct {
+	Self     string `json:"self"`
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Released bool   `json:"released"`
+}
+
+// Sprint is a sprint as returned in the sprint custom field by Jira Cloud.
+type Sprint struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	State string `json:"state"`
+}
+
+// UnmarshalJSON decodes the fields of an issue and keeps the values of the
+// custom fields, which are available through Issue.CustomField.
+func (f *IssueFields) UnmarshalJSON(data []byte) error {
+	type fields IssueFields
+	if err := json.Unmarshal(data, (*fields)(f)); err != nil {
+		return err
+	}
+
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return err
+	}
+
+	f.Custom = nil
+	for id, value := range all {
+		if !strings.HasPrefix(id, customFieldPrefix) || string(value) == "null" {
+			continue
+		}
+
+		if f.Custom == nil {
+			f.Custom = map[string]json.RawMessage{}
+		}
+		f.Custom[id] = value
+	}
+
+	return nil
+}
+
+// CustomField returns the raw value of the custom field called `name`, compared case
+// insensitively, or with id `name`. Field names are only known when the issue was
+// requested with the names expansion.
+func (i *Issue) CustomField(name string) (json.RawMessage, bool) {
+	if value, ok := i.Fields.Custom[name]; ok {
+		return value, true
+	}
+
+	for id, n := range i.Names {
+		if strings.EqualFold(n, name) {
+			value, ok := i.Fields.Custom[id]
+			return value, ok
+		}
+	}
+
+	return nil, false
+}
+
+// CustomFields returns the values of the custom fields by name, as returned by
+// CustomFieldValue. Fields whose name is unknown are returned by id. Named fields
+// without a value, or with a value that can not be represented, are empty strings.
+func (i *Issue) CustomFields() map[string]any {
+	values := make(map[string]any, len(i.Names))
+	for id, n := range i.Names {
+		if strings.HasPrefix(id, customFieldPrefix) && n != "" {
+			values[n] = ""
+		}
+	}
+
+	for id, raw := range i.Fields.Custom {
+		name := id
+		if n, ok := i.Names[id]; ok && n != "" {
+			name = n
+		}
+
+		if value := CustomFieldValue(raw); value != nil {
+			values[name] = value
+		}
+	}
+
+	return values
+}
+
+// CustomFieldValue converts the raw value of a custom field to a string, or to a
+// []string for fields with multiple values. Options, users and other objects are
+// represented by their value, name, display name or key. Returns nil for values
+// that can not be represented.
+func CustomFieldValue(raw json.RawMessage) any {
+	var values []json.RawMessage
+	if err := json.Unmarshal(raw, &values); err == nil {
+		list := make([]string, 0, len(values))
+		for _, v := range values {
+			if s, ok := customFieldString(v); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	}
+
+	if s, ok := customFieldString(raw); ok {
+		return s
+	}
+
+	return nil
+}
+
+func customFieldString(raw json.RawMessage) (string, bool) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s, true
+	}
+
+	var n json.Number
+	if err := json.Unmarshal(raw, &n); err == nil {
+		return n.String(), true
+	}
+
+	var b bool
+	if err := json.Unmarshal(raw, &b); err == nil {
+		return strconv.FormatBool(b), true
+	}
+
+	var obj map[string]any
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return "", false
+	}
+
+	for _, key := range []string{"value", "name", "displayName", "key"} {
+		if s, ok := obj[key].(string); ok && s != "" {
+			return s, true
+		}
+	}
+
+	return "", false
+}
+
+// Sprint returns the name of the active sprint of the issue, or of the last sprint
+// when none is active. Requires the names expansion to find the sprint field.
+func (i *Issue) Sprint() string {
+	raw, ok := i.CustomField(SprintFieldName)
+	if !ok {
+		return ""
+	}
+
+	var sprints []Sprint
+	if err := json.Unmarshal(raw, &sprints); err != nil {
+		// Older versions of Jira Server return the sprints as strings.
+		var legacy []string
+		if err = json.Unmarshal(raw, &legacy); err != nil {
+			return ""
+		}
+
+		for _, s := range legacy {
+			sprints = append(sprints, parseLegacySprint(s))
+		}
+	}
+
+	for _, s := range sprints {
+		if strings.EqualFold(s.State, sprintStateActive) {
+			return s.Name
+		}
+	}
+
+	if len(sprints) == 0 {
+		return ""
+	}
+
+	return sprints[len(sprints)-1].Name
+}
+
+func parseLegacySprint(s string) Sprint {
+	var sprint Sprint
+	if m := legacySprintNameRe.FindStringSubmatch(s); m != nil {
+		sprint.Name = m[1]
+	}
+	if m := legacySprintStateRe.FindStringSubmatch(s); m != nil {
+		sprint.State = m[1]
+	}
+
+	return sprint
+}
+
+// EpicKey returns the key of the epic of the issue: the parent when it is an epic,
+// or the value of the Epic Link field, which requires the names expansion.
+func (i *Issue) EpicKey() string {
+	if p := i.Fields.Parent; p != nil {
+		t := p.Fields.Issuetype
+		if t.HierarchyLevel == epicHierarchyLevel || t.Name == EpicIssueTypeName {
+			return p.Key
+		}
+	}
+
+	if raw, ok := i.CustomField(EpicLinkFieldName); ok {
+		if key, ok := customFieldString(raw); ok {
+			return key
+		}
+	}
+
+	return ""
+}
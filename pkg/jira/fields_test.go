@@ -0,0 +1,123 @@
+package jira_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MaikelVeen/branch/pkg/jira"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const issueWithFields = `{
+	"key": "ABC-1",
+	"names": {
+		"summary": "Summary",
+		"customfield_10020": "Sprint",
+		"customfield_10030": "Team",
+		"customfield_10040": "Story Points",
+		"customfield_10050": "Platforms",
+		"customfield_10060": "Squad"
+	},
+	"fields": {
+		"summary": "Fix the login",
+		"issuetype": {"name": "Story"},
+		"parent": {"key": "ABC-100", "fields": {"summary": "Login", "issuetype": {"name": "Epic", "hierarchyLevel": 1}}},
+		"priority": {"id": "2", "name": "High"},
+		"components": [{"name": "Backend"}, {"name": "API"}],
+		"labels": ["security"],
+		"fixVersions": [{"name": "1.2.0"}],
+		"reporter": {"displayName": "Jane Doe"},
+		"customfield_10020": [
+			{"id": 1, "name": "Sprint 1", "state": "closed"},
+			{"id": 2, "name": "Sprint 2", "state": "active"}
+		],
+		"customfield_10030": {"id": "7", "value": "Payments"},
+		"customfield_10040": 5,
+		"customfield_10050": [{"value": "iOS"}, {"value": "Android"}],
+		"customfield_10060": null
+	}
+}`
+
+func TestIssueFields(t *testing.T) {
+	t.Parallel()
+
+	var issue jira.Issue
+	require.NoError(t, json.Unmarshal([]byte(issueWithFields), &issue))
+
+	assert.Equal(t, "Fix the login", issue.Fields.Summary)
+	assert.Equal(t, "ABC-100", issue.Fields.Parent.Key)
+	assert.Equal(t, "High", issue.Fields.Priority.Name)
+	assert.Len(t, issue.Fields.Components, 2)
+	assert.Equal(t, []string{"security"}, issue.Fields.Labels)
+	assert.Equal(t, "1.2.0", issue.Fields.FixVersions[0].Name)
+	assert.Equal(t, "Jane Doe", issue.Fields.Reporter.DisplayName)
+
+	assert.Len(t, issue.Fields.Custom, 4, "null custom fields are left out")
+	assert.Equal(t, "Sprint 2", issue.Sprint())
+	assert.Equal(t, "ABC-100", issue.EpicKey())
+
+	raw, ok := issue.CustomField("team")
+	require.True(t, ok)
+	assert.Equal(t, "Payments", jira.CustomFieldValue(raw))
+
+	raw, ok = issue.CustomField("customfield_10040")
+	require.True(t, ok)
+	assert.Equal(t, "5", jira.CustomFieldValue(raw))
+
+	_, ok = issue.CustomField("Unknown")
+	assert.False(t, ok)
+
+	assert.Equal(t, map[string]any{
+		"Sprint":       []string{"Sprint 1", "Sprint 2"},
+		"Team":         "Payments",
+		"Story Points": "5",
+		"Platforms":    []string{"iOS", "Android"},
+		"Squad":        "",
+	}, issue.CustomFields(), "named fields without a value are empty")
+}
+
+func TestIssueServerFields(t *testing.T) {
+	t.Parallel()
+
+	// Jira Server returns sprints as strings and links epics with a custom field.
+	data := `{
+		"key": "ABC-1",
+		"names": {"customfield_10100": "Sprint", "customfield_10101": "Epic Link"},
+		"fields": {
+			"customfield_10100": [
+				"com.atlassian.greenhopper.service.sprint.Sprint@1[id=1,rapidViewId=1,state=CLOSED,name=Sprint 1,goal=]",
+				"com.atlassian.greenhopper.service.sprint.Sprint@2[id=2,rapidViewId=1,state=FUTURE,name=Sprint 2,goal=]"
+			],
+			"customfield_10101": "ABC-50"
+		}
+	}`
+
+	var issue jira.Issue
+	require.NoError(t, json.Unmarshal([]byte(data), &issue))
+
+	assert.Equal(t, "Sprint 2", issue.Sprint(), "the last sprint is used when none is active")
+	assert.Equal(t, "ABC-50", issue.EpicKey())
+}
+
+func TestGetIssueExpand(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rest/api/3/issue/ABC-1", r.URL.Path)
+		assert.Equal(t, "names", r.URL.Query().Get("expand"))
+		_, _ = w.Write([]byte(issueWithFields))
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := jira.NewClient(srv.URL)
+	require.NoError(t, err)
+
+	issue, err := client.Issue.GetIssue(context.Background(), "ABC-1", "names")
+	require.NoError(t, err)
+	assert.Equal(t, "Sprint", issue.Names["customfield_10020"])
+	assert.Equal(t, "Sprint 2", issue.Sprint())
+}
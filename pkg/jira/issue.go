@@ -2,16 +2,26 @@ package jira
 
 import (
 	"context"
-	"fmt"
+	"encoding/json"
 	"net/http"
+	"strings"
 )
 
 type IssueResourceService struct {
 	client *Client
 }
 
-func (i *IssueResourceService) GetIssue(ctx context.Context, key string) (*Issue, error) {
-	req, err := i.client.NewRequest(ctx, http.MethodGet, fmt.Sprintf("rest/api/3/issue/%s", key), nil)
+// GetIssue returns the issue identified by `key`. The optional `expand` entities,
+// such as "names", are included in the response.
+//
+// https://developer.atlassian.com/cloud/jira/platform/rest/v3/api-group-issues/#api-rest-api-3-issue-issueidorkey-get
+func (i *IssueResourceService) GetIssue(ctx context.Context, key string, expand ...string) (*Issue, error) {
+	url := i.client.apiPath("issue/%s", key)
+	if len(expand) > 0 {
+		url += "?expand=" + strings.Join(expand, ",")
+	}
+
+	req, err := i.client.NewRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -25,18 +35,74 @@ func (i *IssueResourceService) GetIssue(ctx context.Context, key string) (*Issue
 	return issue, nil
 }
 
+// AssignIssue assigns the issue identified by `key` to `user`. Jira Cloud identifies
+// users by their account id, Jira Server by their username.
+//
+// https://developer.atlassian.com/cloud/jira/platform/rest/v3/api-group-issues/#api-rest-api-3-issue-issueidorkey-assignee-put
+func (i *IssueResourceService) AssignIssue(ctx context.Context, key string, user *User) error {
+	body := map[string]string{"accountId": user.AccountID}
+	if i.client.deployment == DeploymentServer {
+		body = map[string]string{"name": user.Name}
+	}
+
+	req, err := i.client.NewRequest(ctx, http.MethodPut, i.client.apiPath("issue/%s/assignee", key), body)
+	if err != nil {
+		return err
+	}
+
+	return i.client.Do(req, nil)
+}
+
+// AddComment adds a plain text comment to the issue identified by `key`.
+//
+// https://developer.atlassian.com/cloud/jira/platform/rest/v3/api-group-issue-comments/#api-rest-api-3-issue-issueidorkey-comment-post
+func (i *IssueResourceService) AddComment(ctx context.Context, key, text string) (*Comment, error) {
+	// Version 2 of the API takes wiki markup instead of a document.
+	body := map[string]any{"body": NewTextDocument(text)}
+	if i.client.apiVersion == "2" {
+		body = map[string]any{"body": text}
+	}
+
+	req, err := i.client.NewRequest(ctx, http.MethodPost, i.client.apiPath("issue/%s/comment", key), body)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := new(Comment)
+	if err = i.client.Do(req, comment); err != nil {
+		return nil, err
+	}
+
+	return comment, nil
+}
+
 type Issue struct {
 	Expand string      `json:"expand"`
 	ID     string      `json:"id"`
 	Self   string      `json:"self"`
 	Key    string      `json:"key"`
 	Fields IssueFields `json:"fields"`
+	// Names maps the ids of the fields to their names, when requested with the names expansion.
+	Names map[string]string `json:"names,omitempty"`
 }
 
 type IssueFields struct {
 	Issuetype IssueType `json:"issuetype"`
 	Updated   string    `json:"updated"`
 	Summary   string    `json:"summary"`
+	Status    Status    `json:"status"`
+	Project   Project   `json:"project"`
+	Assignee  *User     `json:"assignee"`
+
+	Parent      *Parent     `json:"parent,omitempty"`
+	Priority    *Priority   `json:"priority,omitempty"`
+	Components  []Component `json:"components,omitempty"`
+	Labels      []string    `json:"labels,omitempty"`
+	FixVersions []Version   `json:"fixVersions,omitempty"`
+	Reporter    *User       `json:"reporter,omitempty"`
+
+	// Custom holds the raw values of the custom fields by id.
+	Custom map[string]json.RawMessage `json:"-"`
 }
 
 type IssueType struct {
@@ -49,3 +115,63 @@ type IssueType struct {
 	AvatarID       int64  `json:"avatarId"`
 	HierarchyLevel int64  `json:"hierarchyLevel"`
 }
+
+type Status struct {
+	Self           string         `json:"self"`
+	Description    string         `json:"description"`
+	IconURL        string         `json:"iconUrl"`
+	Name           string         `json:"name"`
+	ID             string         `json:"id"`
+	StatusCategory StatusCategory `json:"statusCategory"`
+}
+
+type StatusCategory struct {
+	Self      string `json:"self"`
+	ID        int64  `json:"id"`
+	Key       string `json:"key"`
+	ColorName string `json:"colorName"`
+	Name      string `json:"name"`
+}
+
+// Keys of the status categories every status belongs to.
+const (
+	StatusCategoryNew        = "new"
+	StatusCategoryInProgress = "indeterminate"
+	StatusCategoryDone       = "done"
+)
+
+type Project struct {
+	Self           string `json:"self"`
+	ID             string `json:"id"`
+	Key            string `json:"key"`
+	Name           string `json:"name"`
+	ProjectTypeKey string `json:"projectTypeKey"`
+}
+
+type Comment struct {
+	Self    string `json:"self"`
+	ID      string `json:"id"`
+	Created string `json:"created"`
+}
+
+// Document is a node in the Atlassian Document Format, used for rich text fields.
+//
+// https://developer.atlassian.com/cloud/jira/platform/apis/document/structure/
+type Document struct {
+	Type    string     `json:"type"`
+	Version int        `json:"version,omitempty"`
+	Text    string     `json:"text,omitempty"`
+	Content []Document `json:"content,omitempty"`
+}
+
+// NewTextDocument returns a document with a single paragraph containing `text`.
+func NewTextDocument(text string) Document {
+	return Document{
+		Type:    "doc",
+		Version: 1,
+		Content: []Document{{
+			Type:    "paragraph",
+			Content: []Document{{Type: "text", Text: text}},
+		}},
+	}
+}
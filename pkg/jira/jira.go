@@ -9,6 +9,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 )
 
@@ -20,11 +21,37 @@ const (
 	DefaultTimeout = 30 * time.Second
 )
 
+// Deployment is the type of Jira installation the client talks to.
+type Deployment string
+
+const (
+	// DeploymentCloud is Jira Cloud, which supports version 3 of the REST API.
+	DeploymentCloud Deployment = "cloud"
+	// DeploymentServer is Jira Server or Data Center, which only supports version 2.
+	DeploymentServer Deployment = "server"
+)
+
+// ParseDeployment parses `s` into a Deployment. An empty string defaults to DeploymentCloud.
+func ParseDeployment(s string) (Deployment, error) {
+	switch Deployment(strings.ToLower(s)) {
+	case DeploymentCloud, "":
+		return DeploymentCloud, nil
+	case DeploymentServer, "datacenter", "data-center":
+		return DeploymentServer, nil
+	default:
+		return "", fmt.Errorf("unknown deployment type %q, expected cloud or server", s)
+	}
+}
+
 // Client manages communication with the Jira API.
 type Client struct {
-	client   *http.Client
-	username string
-	token    string
+	client     *http.Client
+	username   string
+	token      string
+	bearer     string
+	retry      *RetryPolicy
+	deployment Deployment
+	apiVersion string
 
 	// BaseURL is the base URL for the Jira API.
 	BaseURL url.URL
@@ -37,6 +64,7 @@ type Client struct {
 
 	Issue  *IssueResourceService
 	Myself *MyselfResourceService
+	Search *SearchResourceService
 }
 
 // NewClient returns a new client with the given options.
@@ -46,9 +74,17 @@ func NewClient(baseURL string, opts ...func(*Client) error) (*Client, error) {
 		return nil, err
 	}
 
+	// Paths are resolved relative to the base URL, so it must end in a slash to
+	// keep a path such as the context path of a Jira Server instance.
+	if !strings.HasSuffix(url.Path, "/") {
+		url.Path += "/"
+	}
+
 	client := &Client{
-		BaseURL: *url,
-		client:  &http.Client{Timeout: DefaultTimeout},
+		BaseURL:    *url,
+		client:     &http.Client{Timeout: DefaultTimeout},
+		deployment: DeploymentCloud,
+		apiVersion: "3",
 	}
 
 	for _, opt := range opts {
@@ -60,6 +96,7 @@ func NewClient(baseURL string, opts ...func(*Client) error) (*Client, error) {
 
 	client.Issue = &IssueResourceService{client: client}
 	client.Myself = &MyselfResourceService{client: client}
+	client.Search = &SearchResourceService{client: client}
 
 	return client, nil
 }
@@ -89,6 +126,43 @@ func WithBasicAuthentication(username, token string) func(*Client) error {
 	}
 }
 
+// WithBearerAuthentication returns an option to authenticate with a bearer token,
+// such as a Jira Server or Data Center personal access token.
+func WithBearerAuthentication(token string) func(*Client) error {
+	return func(c *Client) error {
+		c.bearer = token
+		return nil
+	}
+}
+
+// WithDeployment returns an option to set the deployment type of the Jira instance.
+// Server deployments use version 2 of the REST API.
+func WithDeployment(d Deployment) func(*Client) error {
+	return func(c *Client) error {
+		switch d {
+		case DeploymentCloud:
+			c.apiVersion = "3"
+		case DeploymentServer:
+			c.apiVersion = "2"
+		default:
+			return fmt.Errorf("unknown deployment type %q", d)
+		}
+
+		c.deployment = d
+		return nil
+	}
+}
+
+// Deployment returns the deployment type of the Jira instance.
+func (c *Client) Deployment() Deployment {
+	return c.deployment
+}
+
+// apiPath returns the path of `format`, formatted with `args`, in the REST API version used by the client.
+func (c *Client) apiPath(format string, args ...any) string {
+	return fmt.Sprintf("rest/api/%s/", c.apiVersion) + fmt.Sprintf(format, args...)
+}
+
 // BasicAuthentication returns the username and token user-id/password pair, encoded using Base64.
 // See: https://datatracker.ietf.org/doc/html/rfc7617
 func BasicAuthentication(username, token string) string {
@@ -125,7 +199,10 @@ func (c *Client) NewRequest(ctx context.Context, method, urlStr string, body int
 		req.Header.Set("User-Agent", c.UserAgent)
 	}
 
-	if c.token != "" {
+	switch {
+	case c.bearer != "":
+		req.Header.Set("Authorization", "Bearer "+c.bearer)
+	case c.token != "":
 		req.Header.Set("Authorization", BasicAuthentication(c.username, c.token))
 	}
 
@@ -133,10 +210,10 @@ func (c *Client) NewRequest(ctx context.Context, method, urlStr string, body int
 }
 
 // Do sends an API request and returns the API response. The API response is
-// decoded and stored in the value pointed to by v, or returned as an error if
-// an API error has occurred.
+// decoded and stored in the value pointed to by v, or returned as an
+// *ErrorResponse if an API error has occurred.
 func (c *Client) Do(req *http.Request, v interface{}) error {
-	resp, err := c.client.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return err
 	}
@@ -144,7 +221,7 @@ func (c *Client) Do(req *http.Request, v interface{}) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= http.StatusBadRequest {
-		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+		return newErrorResponse(resp)
 	}
 
 	if v != nil {
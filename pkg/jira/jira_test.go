@@ -0,0 +1,149 @@
+package jira_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MaikelVeen/branch/pkg/jira"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDeployment(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		input   string
+		expect  jira.Deployment
+		wantErr bool
+	}{
+		"empty defaults to cloud": {input: "", expect: jira.DeploymentCloud},
+		"cloud":                   {input: "Cloud", expect: jira.DeploymentCloud},
+		"server":                  {input: "server", expect: jira.DeploymentServer},
+		"data center":             {input: "datacenter", expect: jira.DeploymentServer},
+		"unknown":                 {input: "on-prem", wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			d, err := jira.ParseDeployment(tc.input)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expect, d)
+		})
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		opts   []func(*jira.Client) error
+		expect string
+	}{
+		"basic authentication": {
+			opts:   []func(*jira.Client) error{jira.WithBasicAuthentication("user@example.com", "token")},
+			expect: jira.BasicAuthentication("user@example.com", "token"),
+		},
+		"bearer authentication": {
+			opts:   []func(*jira.Client) error{jira.WithBearerAuthentication("pat")},
+			expect: "Bearer pat",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client, err := jira.NewClient("https://jira.example.com", tc.opts...)
+			require.NoError(t, err)
+
+			req, err := client.NewRequest(context.Background(), http.MethodGet, "rest/api/2/myself", nil)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expect, req.Header.Get("Authorization"))
+		})
+	}
+}
+
+func TestServerDeployment(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/2/search":
+			_ = json.NewEncoder(w).Encode(jira.SearchResults{Total: 1, Issues: []jira.Issue{{Key: "SRV-1"}}})
+		case "/rest/api/2/issue/SRV-1/comment":
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			assert.JSONEq(t, `{"body":"hello"}`, string(body))
+			_, _ = w.Write([]byte(`{"id":"1"}`))
+		case "/rest/api/2/issue/SRV-1/assignee":
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			assert.JSONEq(t, `{"name":"jdoe"}`, string(body))
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := jira.NewClient(srv.URL, jira.WithDeployment(jira.DeploymentServer))
+	require.NoError(t, err)
+	assert.Equal(t, jira.DeploymentServer, client.Deployment())
+
+	issues, err := client.Search.Collect(context.Background(), "project = SRV", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"SRV-1"}, keys(issues))
+
+	_, err = client.Issue.AddComment(context.Background(), "SRV-1", "hello")
+	require.NoError(t, err)
+
+	require.NoError(t, client.Issue.AssignIssue(context.Background(), "SRV-1", &jira.User{Name: "jdoe"}))
+}
+
+func TestBaseURLWithPath(t *testing.T) {
+	t.Parallel()
+
+	var path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := jira.NewClient(srv.URL + "/ex/jira/cloud-id")
+	require.NoError(t, err)
+
+	_, err = c.Myself.Myself(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "/ex/jira/cloud-id/rest/api/3/myself", path)
+}
+
+func TestExtractIssueKey(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]string{
+		"feature/ABC-123-add-login": "ABC-123",
+		"ABC_2-7":                   "ABC_2-7",
+		"bug/X1-2/X1-3":             "X1-2",
+		"main":                      "",
+		"release-1.2":               "",
+		"abc-123-lowercase":         "",
+	}
+
+	for branch, want := range testCases {
+		assert.Equal(t, want, jira.ExtractIssueKey(branch), branch)
+	}
+}
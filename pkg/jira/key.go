@@ -0,0 +1,12 @@
+package jira
+
+import "regexp"
+
+// IssueKeyPattern matches issue keys such as ABC-123.
+var IssueKeyPattern = regexp.MustCompile(`[A-Z][A-Z0-9_]+-\d+`)
+
+// ExtractIssueKey returns the first issue key in `s`, such as a branch
+// name, or an empty string when there is none.
+func ExtractIssueKey(s string) string {
+	return IssueKeyPattern.FindString(s)
+}
@@ -10,7 +10,7 @@ type MyselfResourceService struct {
 }
 
 func (m *MyselfResourceService) Myself(ctx context.Context) (*User, error) {
-	req, err := m.client.NewRequest(ctx, http.MethodGet, "rest/api/3/myself", nil)
+	req, err := m.client.NewRequest(ctx, http.MethodGet, m.client.apiPath("myself"), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,188 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+const (
+	// DefaultMaxRetries is the number of retries used by DefaultRetryPolicy.
+	DefaultMaxRetries = 4
+	// DefaultMinBackoff is the initial backoff used by DefaultRetryPolicy.
+	DefaultMinBackoff = 500 * time.Millisecond
+	// DefaultMaxBackoff is the backoff cap used by DefaultRetryPolicy.
+	DefaultMaxBackoff = 30 * time.Second
+)
+
+// RetryPolicy configures how the client retries requests that were rejected
+// because of rate limiting or a temporarily unavailable service.
+//
+// https://developer.atlassian.com/cloud/jira/platform/rate-limiting/
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of retries after the first attempt.
+	MaxRetries int
+	// MinBackoff is the backoff before the first retry, it doubles on each attempt.
+	MinBackoff time.Duration
+	// MaxBackoff caps the backoff. A server provided delay that exceeds it
+	// is not waited for and the error is returned instead.
+	MaxBackoff time.Duration
+	// RetryNonIdempotent enables retries for methods such as POST.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy returns the recommended retry policy.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: DefaultMaxRetries,
+		MinBackoff: DefaultMinBackoff,
+		MaxBackoff: DefaultMaxBackoff,
+	}
+}
+
+// WithRetry returns an option that retries rate limited (429) and
+// unavailable (503) responses according to `policy`.
+func WithRetry(policy RetryPolicy) func(*Client) error {
+	return func(c *Client) error {
+		if policy.MaxRetries < 0 {
+			return errors.New("max retries must not be negative")
+		}
+
+		if policy.MinBackoff <= 0 || policy.MaxBackoff < policy.MinBackoff {
+			return errors.New("backoff must be positive and max backoff must not be less than min backoff")
+		}
+
+		c.retry = &policy
+		return nil
+	}
+}
+
+// send executes `req`, retrying it when the retry policy allows.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	if c.retry == nil || !c.retry.allowsMethod(req.Method) {
+		return c.client.Do(req)
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if attempt >= c.retry.MaxRetries || !isRetryableStatus(resp.StatusCode) {
+			return resp, nil
+		}
+
+		delay, ok := c.retry.delay(attempt, resp.Header, time.Now())
+		if !ok {
+			return resp, nil
+		}
+
+		// Drain the body so the connection can be reused.
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		if err = wait(req.Context(), delay); err != nil {
+			return nil, err
+		}
+
+		if req, err = rewind(req); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *RetryPolicy) allowsMethod(method string) bool {
+	if p.RetryNonIdempotent {
+		return true
+	}
+
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete, http.MethodTrace:
+		return true
+	default:
+		return false
+	}
+}
+
+// delay returns how long to wait before retry number `attempt`. A delay requested
+// by the server is preferred over exponential backoff. The second return value is
+// false when the requested delay exceeds MaxBackoff.
+func (p *RetryPolicy) delay(attempt int, header http.Header, now time.Time) (time.Duration, bool) {
+	hint := parseRetryAfter(header.Get("Retry-After"), now)
+	if hint == 0 {
+		hint = parseRateLimitReset(header.Get("X-RateLimit-Reset"), now)
+	}
+
+	if hint > 0 {
+		return hint, hint <= p.MaxBackoff
+	}
+
+	backoff := p.MinBackoff << attempt
+	if backoff > p.MaxBackoff || backoff <= 0 {
+		backoff = p.MaxBackoff
+	}
+
+	// Equal jitter: wait at least half of the backoff to spread out clients
+	// that were rate limited at the same time.
+	half := backoff / 2
+	return half + rand.N(half+1), true //nolint:gosec // jitter does not need a secure random source
+}
+
+// parseRateLimitReset parses the X-RateLimit-Reset header, an ISO 8601 timestamp.
+func parseRateLimitReset(v string, now time.Time) time.Duration {
+	if v == "" {
+		return 0
+	}
+
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04Z07:00"} {
+		if t, err := time.Parse(layout, v); err == nil {
+			if t.After(now) {
+				return t.Sub(now)
+			}
+			return 0
+		}
+	}
+
+	return 0
+}
+
+func isRetryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code == http.StatusServiceUnavailable
+}
+
+// wait blocks for `d` or until the context is done.
+func wait(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// rewind returns a copy of `req` with a fresh body so it can be sent again.
+func rewind(req *http.Request) (*http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, nil
+	}
+
+	if req.GetBody == nil {
+		return nil, errors.New("request body cannot be rewound for a retry")
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+
+	clone := req.Clone(req.Context())
+	clone.Body = body
+	return clone, nil
+}
@@ -0,0 +1,166 @@
+package jira_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/MaikelVeen/branch/pkg/jira"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRetry(t *testing.T) {
+	t.Parallel()
+
+	policy := jira.RetryPolicy{
+		MaxRetries: 3,
+		MinBackoff: time.Millisecond,
+		MaxBackoff: 2 * time.Second,
+	}
+
+	// failingServer responds with `status` for the first `failures` requests.
+	failingServer := func(t *testing.T, failures int32, status int, header map[string]string) (*httptest.Server, *atomic.Int32) {
+		t.Helper()
+
+		var calls atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Body != nil {
+				body, _ := io.ReadAll(r.Body)
+				if r.Method == http.MethodPut {
+					assert.JSONEq(t, `{"name":"value"}`, string(body))
+				}
+			}
+
+			if calls.Add(1) <= failures {
+				for k, v := range header {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(status)
+				return
+			}
+			_, _ = w.Write([]byte(`{}`))
+		}))
+		t.Cleanup(srv.Close)
+
+		return srv, &calls
+	}
+
+	t.Run("retries rate limited requests until success", func(t *testing.T) {
+		t.Parallel()
+
+		srv, calls := failingServer(t, 2, http.StatusTooManyRequests, nil)
+		client, err := jira.NewClient(srv.URL, jira.WithRetry(policy))
+		require.NoError(t, err)
+
+		_, err = client.Myself.Myself(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("retries unavailable responses and rewinds the body", func(t *testing.T) {
+		t.Parallel()
+
+		srv, calls := failingServer(t, 1, http.StatusServiceUnavailable, nil)
+		client, err := jira.NewClient(srv.URL, jira.WithRetry(policy))
+		require.NoError(t, err)
+
+		req, err := client.NewRequest(context.Background(), http.MethodPut, "rest/api/3/anything", map[string]string{"name": "value"})
+		require.NoError(t, err)
+		require.NoError(t, client.Do(req, nil))
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("gives up after max retries", func(t *testing.T) {
+		t.Parallel()
+
+		srv, calls := failingServer(t, 10, http.StatusTooManyRequests, nil)
+		client, err := jira.NewClient(srv.URL, jira.WithRetry(policy))
+		require.NoError(t, err)
+
+		_, err = client.Myself.Myself(context.Background())
+		assert.True(t, jira.IsRateLimited(err))
+		assert.Equal(t, int32(4), calls.Load())
+	})
+
+	t.Run("does not retry non idempotent methods by default", func(t *testing.T) {
+		t.Parallel()
+
+		srv, calls := failingServer(t, 1, http.StatusTooManyRequests, nil)
+		client, err := jira.NewClient(srv.URL, jira.WithRetry(policy))
+		require.NoError(t, err)
+
+		req, err := client.NewRequest(context.Background(), http.MethodPost, "rest/api/3/anything", nil)
+		require.NoError(t, err)
+		assert.True(t, jira.IsRateLimited(client.Do(req, nil)))
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("does not retry other errors", func(t *testing.T) {
+		t.Parallel()
+
+		srv, calls := failingServer(t, 1, http.StatusInternalServerError, nil)
+		client, err := jira.NewClient(srv.URL, jira.WithRetry(policy))
+		require.NoError(t, err)
+
+		_, err = client.Myself.Myself(context.Background())
+		require.Error(t, err)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("honours retry after", func(t *testing.T) {
+		t.Parallel()
+
+		srv, calls := failingServer(t, 1, http.StatusTooManyRequests, map[string]string{"Retry-After": "1"})
+		client, err := jira.NewClient(srv.URL, jira.WithRetry(policy))
+		require.NoError(t, err)
+
+		start := time.Now()
+		_, err = client.Myself.Myself(context.Background())
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, time.Since(start), time.Second)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("honours rate limit reset", func(t *testing.T) {
+		t.Parallel()
+
+		reset := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+		srv, calls := failingServer(t, 1, http.StatusTooManyRequests, map[string]string{"X-RateLimit-Reset": reset})
+		client, err := jira.NewClient(srv.URL, jira.WithRetry(policy))
+		require.NoError(t, err)
+
+		// The reset is further away than MaxBackoff so the error is returned immediately.
+		_, err = client.Myself.Myself(context.Background())
+		assert.True(t, jira.IsRateLimited(err))
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("stops waiting when the context is cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		srv, _ := failingServer(t, 10, http.StatusTooManyRequests, map[string]string{"Retry-After": "1"})
+		client, err := jira.NewClient(srv.URL, jira.WithRetry(policy))
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		t.Cleanup(cancel)
+
+		_, err = client.Myself.Myself(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("invalid policies are rejected", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := jira.NewClient("https://example.atlassian.net", jira.WithRetry(jira.RetryPolicy{MaxRetries: -1}))
+		require.Error(t, err)
+
+		_, err = jira.NewClient("https://example.atlassian.net", jira.WithRetry(jira.RetryPolicy{}))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,323 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultSearchPageSize is the number of issues requested per page
+	// when no explicit page size is given.
+	DefaultSearchPageSize = 50
+
+	// maxKeysPerQuery limits the number of keys in a single `key in (...)` query.
+	maxKeysPerQuery = 100
+)
+
+// SearchEndpoint selects the search endpoint used to page through results.
+type SearchEndpoint int
+
+const (
+	// SearchEndpointAuto lets the client pick the best endpoint for the deployment.
+	SearchEndpointAuto SearchEndpoint = iota
+	// SearchEndpointJQL uses /search/jql, which pages with a nextPageToken.
+	SearchEndpointJQL
+	// SearchEndpointLegacy uses /search, which pages with startAt and maxResults.
+	SearchEndpointLegacy
+)
+
+type SearchResourceService struct {
+	client *Client
+}
+
+// SearchOptions controls which data is returned by a search.
+type SearchOptions struct {
+	// Fields is the list of fields to return for each issue. When empty
+	// the default navigable fields are returned.
+	Fields []string
+	// Expand is the list of entities to expand, for example "names".
+	Expand []string
+	// MaxResults is the page size. Defaults to DefaultSearchPageSize.
+	MaxResults int
+	// Endpoint selects the endpoint used by the iterator.
+	Endpoint SearchEndpoint
+}
+
+// SearchResults is a single page returned by the offset based /search endpoint.
+type SearchResults struct {
+	Expand     string  `json:"expand"`
+	StartAt    int     `json:"startAt"`
+	MaxResults int     `json:"maxResults"`
+	Total      int     `json:"total"`
+	Issues     []Issue `json:"issues"`
+}
+
+// SearchJQLResults is a single page returned by the token based /search/jql endpoint.
+type SearchJQLResults struct {
+	Issues        []Issue `json:"issues"`
+	NextPageToken string  `json:"nextPageToken"`
+	IsLast        bool    `json:"isLast"`
+}
+
+// Search runs `jql` against the /search endpoint and returns the page starting at `startAt`.
+//
+// https://developer.atlassian.com/cloud/jira/platform/rest/v3/api-group-issue-search/#api-rest-api-3-search-get
+func (s *SearchResourceService) Search(
+	ctx context.Context,
+	jql string,
+	startAt int,
+	opts *SearchOptions,
+) (*SearchResults, error) {
+	q := searchQuery(jql, opts)
+	q.Set("startAt", strconv.Itoa(startAt))
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, s.client.apiPath("search")+"?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	results := new(SearchResults)
+	if err = s.client.Do(req, results); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// SearchJQL runs `jql` against the /search/jql endpoint and returns the page identified
+// by `nextPageToken`. An empty token returns the first page. This endpoint is only
+// available on Jira Cloud.
+//
+// https://developer.atlassian.com/cloud/jira/platform/rest/v3/api-group-issue-search/#api-rest-api-3-search-jql-get
+func (s *SearchResourceService) SearchJQL(
+	ctx context.Context,
+	jql string,
+	nextPageToken string,
+	opts *SearchOptions,
+) (*SearchJQLResults, error) {
+	q := searchQuery(jql, opts)
+	if nextPageToken != "" {
+		q.Set("nextPageToken", nextPageToken)
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, s.client.apiPath("search/jql")+"?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	results := new(SearchJQLResults)
+	if err = s.client.Do(req, results); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// All returns an iterator that walks every issue matching `jql`,
+// fetching additional pages as needed.
+func (s *SearchResourceService) All(jql string, opts *SearchOptions) *SearchIterator {
+	if opts == nil {
+		opts = &SearchOptions{}
+	}
+
+	return &SearchIterator{
+		service:  s,
+		jql:      jql,
+		opts:     opts,
+		endpoint: s.resolveEndpoint(opts.Endpoint),
+	}
+}
+
+// Collect is a convenience wrapper around All that returns every matching issue.
+func (s *SearchResourceService) Collect(ctx context.Context, jql string, opts *SearchOptions) ([]Issue, error) {
+	var issues []Issue
+
+	it := s.All(jql, opts)
+	for it.Next(ctx) {
+		issues = append(issues, *it.Issue())
+	}
+
+	return issues, it.Err()
+}
+
+// ByKeys fetches the issues identified by `keys` with `key in (...)` queries.
+// Keys of issues that do not exist or are not visible are left out of the result.
+func (s *SearchResourceService) ByKeys(ctx context.Context, keys []string, opts *SearchOptions) ([]Issue, error) {
+	var issues []Issue
+
+	for start := 0; start < len(keys); start += maxKeysPerQuery {
+		batch, err := s.byKeys(ctx, keys[start:min(start+maxKeysPerQuery, len(keys))], opts)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, batch...)
+	}
+
+	return issues, nil
+}
+
+// byKeys runs a single `key in (...)` query. Jira rejects the whole query when
+// one of the keys does not exist, so those keys are dropped and the query retried.
+func (s *SearchResourceService) byKeys(ctx context.Context, keys []string, opts *SearchOptions) ([]Issue, error) {
+	for len(keys) > 0 {
+		issues, err := s.Collect(ctx, "key in ("+strings.Join(keys, ",")+")", opts)
+		if err == nil {
+			return issues, nil
+		}
+
+		var apiErr *ErrorResponse
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+			return nil, err
+		}
+
+		remaining := keys[:0:0]
+		for _, key := range keys {
+			if !mentionsKey(apiErr, key) {
+				remaining = append(remaining, key)
+			}
+		}
+
+		if len(remaining) == len(keys) {
+			return nil, err
+		}
+		keys = remaining
+	}
+
+	return nil, nil
+}
+
+// mentionsKey reports whether one of the error messages of `e` refers to `key`.
+func mentionsKey(e *ErrorResponse, key string) bool {
+	for _, msg := range e.ErrorMessages {
+		if strings.Contains(msg, "'"+key+"'") || strings.Contains(msg, "\""+key+"\"") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *SearchResourceService) resolveEndpoint(e SearchEndpoint) SearchEndpoint {
+	if e != SearchEndpointAuto {
+		return e
+	}
+
+	if s.client.deployment == DeploymentServer {
+		return SearchEndpointLegacy
+	}
+
+	return SearchEndpointJQL
+}
+
+// SearchIterator pages through search results. Use it as follows:
+//
+//	it := client.Search.All(jql, nil)
+//	for it.Next(ctx) {
+//		issue := it.Issue()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type SearchIterator struct {
+	service  *SearchResourceService
+	jql      string
+	opts     *SearchOptions
+	endpoint SearchEndpoint
+
+	page    []Issue
+	index   int
+	current *Issue
+	err     error
+
+	done      bool
+	startAt   int
+	nextToken string
+}
+
+// Next advances the iterator to the next issue, fetching a new page if needed.
+// It returns false when there are no more issues or an error occurred.
+func (it *SearchIterator) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+
+	for it.index >= len(it.page) {
+		if it.done {
+			return false
+		}
+
+		if err := it.fetch(ctx); err != nil {
+			it.err = err
+			return false
+		}
+	}
+
+	it.current = &it.page[it.index]
+	it.index++
+	return true
+}
+
+// Issue returns the current issue.
+func (it *SearchIterator) Issue() *Issue {
+	return it.current
+}
+
+// Err returns the first error encountered while iterating.
+func (it *SearchIterator) Err() error {
+	return it.err
+}
+
+func (it *SearchIterator) fetch(ctx context.Context) error {
+	it.index = 0
+
+	if it.endpoint == SearchEndpointLegacy {
+		res, err := it.service.Search(ctx, it.jql, it.startAt, it.opts)
+		if err != nil {
+			return err
+		}
+
+		it.page = res.Issues
+		it.startAt += len(res.Issues)
+		it.done = len(res.Issues) == 0 || it.startAt >= res.Total
+		return nil
+	}
+
+	res, err := it.service.SearchJQL(ctx, it.jql, it.nextToken, it.opts)
+	if err != nil {
+		return err
+	}
+
+	it.page = res.Issues
+	it.nextToken = res.NextPageToken
+	it.done = res.IsLast || res.NextPageToken == ""
+	return nil
+}
+
+func searchQuery(jql string, opts *SearchOptions) url.Values {
+	q := url.Values{}
+	q.Set("jql", jql)
+
+	maxResults := DefaultSearchPageSize
+	if opts != nil && opts.MaxResults > 0 {
+		maxResults = opts.MaxResults
+	}
+	q.Set("maxResults", strconv.Itoa(maxResults))
+
+	if opts == nil {
+		return q
+	}
+
+	if len(opts.Fields) > 0 {
+		q.Set("fields", strings.Join(opts.Fields, ","))
+	}
+
+	if len(opts.Expand) > 0 {
+		q.Set("expand", strings.Join(opts.Expand, ","))
+	}
+
+	return q
+}
@@ -0,0 +1,147 @@
+package jira_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/MaikelVeen/branch/pkg/jira"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchIterator(t *testing.T) {
+	t.Parallel()
+
+	t.Run("jql endpoint follows next page tokens", func(t *testing.T) {
+		t.Parallel()
+
+		pages := map[string]jira.SearchJQLResults{
+			"":   {Issues: []jira.Issue{{Key: "A-1"}, {Key: "A-2"}}, NextPageToken: "p2"},
+			"p2": {Issues: []jira.Issue{{Key: "A-3"}}, IsLast: true},
+		}
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/rest/api/3/search/jql", r.URL.Path)
+			assert.Equal(t, "project = A", r.URL.Query().Get("jql"))
+			assert.Equal(t, "summary,status", r.URL.Query().Get("fields"))
+			assert.Equal(t, "names", r.URL.Query().Get("expand"))
+
+			page, ok := pages[r.URL.Query().Get("nextPageToken")]
+			require.True(t, ok)
+			_ = json.NewEncoder(w).Encode(page)
+		}))
+		t.Cleanup(srv.Close)
+
+		client, err := jira.NewClient(srv.URL)
+		require.NoError(t, err)
+
+		issues, err := client.Search.Collect(context.Background(), "project = A", &jira.SearchOptions{
+			Fields: []string{"summary", "status"},
+			Expand: []string{"names"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"A-1", "A-2", "A-3"}, keys(issues))
+	})
+
+	t.Run("legacy endpoint pages with startAt", func(t *testing.T) {
+		t.Parallel()
+
+		const total = 5
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/rest/api/3/search", r.URL.Path)
+			assert.Equal(t, "2", r.URL.Query().Get("maxResults"))
+
+			startAt, err := strconv.Atoi(r.URL.Query().Get("startAt"))
+			require.NoError(t, err)
+
+			res := jira.SearchResults{StartAt: startAt, MaxResults: 2, Total: total}
+			for i := startAt; i < startAt+2 && i < total; i++ {
+				res.Issues = append(res.Issues, jira.Issue{Key: fmt.Sprintf("B-%d", i+1)})
+			}
+			_ = json.NewEncoder(w).Encode(res)
+		}))
+		t.Cleanup(srv.Close)
+
+		client, err := jira.NewClient(srv.URL)
+		require.NoError(t, err)
+
+		issues, err := client.Search.Collect(context.Background(), "project = B", &jira.SearchOptions{
+			MaxResults: 2,
+			Endpoint:   jira.SearchEndpointLegacy,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"B-1", "B-2", "B-3", "B-4", "B-5"}, keys(issues))
+	})
+
+	t.Run("errors stop the iteration", func(t *testing.T) {
+		t.Parallel()
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		t.Cleanup(srv.Close)
+
+		client, err := jira.NewClient(srv.URL)
+		require.NoError(t, err)
+
+		it := client.Search.All("invalid", nil)
+		assert.False(t, it.Next(context.Background()))
+		require.Error(t, it.Err())
+	})
+}
+
+func TestSearchByKeys(t *testing.T) {
+	t.Parallel()
+
+	existing := map[string]bool{"A-1": true, "A-3": true}
+	var queries []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jql := r.URL.Query().Get("jql")
+		queries = append(queries, jql)
+
+		// Jira rejects the whole query when one of the keys does not exist.
+		var res jira.SearchJQLResults
+		var missing []string
+		for _, key := range strings.Split(strings.TrimSuffix(strings.TrimPrefix(jql, "key in ("), ")"), ",") {
+			if existing[key] {
+				res.Issues = append(res.Issues, jira.Issue{Key: key})
+			} else {
+				missing = append(missing, fmt.Sprintf("An issue with key '%s' does not exist for field 'key'.", key))
+			}
+		}
+
+		if len(missing) > 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]any{"errorMessages": missing})
+			return
+		}
+
+		res.IsLast = true
+		_ = json.NewEncoder(w).Encode(res)
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := jira.NewClient(srv.URL)
+	require.NoError(t, err)
+
+	issues, err := client.Search.ByKeys(context.Background(), []string{"A-1", "A-2", "A-3"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"A-1", "A-3"}, keys(issues))
+	assert.Equal(t, []string{"key in (A-1,A-2,A-3)", "key in (A-1,A-3)"}, queries)
+}
+
+func keys(issues []jira.Issue) []string {
+	k := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		k = append(k, issue.Key)
+	}
+
+	return k
+}
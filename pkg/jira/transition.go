@@ -0,0 +1,143 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// ErrTransitionNotFound is returned when no transition matches the requested name.
+var ErrTransitionNotFound = errors.New("transition not found")
+
+// GetTransitions returns the transitions that are available from the current status
+// of the issue identified by `key`, including the fields of their transition screens.
+//
+// https://developer.atlassian.com/cloud/jira/platform/rest/v3/api-group-issues/#api-rest-api-3-issue-issueidorkey-transitions-get
+func (i *IssueResourceService) GetTransitions(ctx context.Context, key string) ([]Transition, error) {
+	url := i.client.apiPath("issue/%s/transitions?expand=transitions.fields", key)
+	req, err := i.client.NewRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res := new(Transitions)
+	if err = i.client.Do(req, res); err != nil {
+		return nil, err
+	}
+
+	return res.Transitions, nil
+}
+
+// DoTransition moves the issue identified by `key` through the transition with `id`.
+// The optional `fields` are set on the transition screen.
+//
+// https://developer.atlassian.com/cloud/jira/platform/rest/v3/api-group-issues/#api-rest-api-3-issue-issueidorkey-transitions-post
+func (i *IssueResourceService) DoTransition(ctx context.Context, key, id string, fields map[string]any) error {
+	body := transitionRequest{
+		Transition: transitionID{ID: id},
+		Fields:     fields,
+	}
+
+	req, err := i.client.NewRequest(ctx, http.MethodPost, i.client.apiPath("issue/%s/transitions", key), body)
+	if err != nil {
+		return err
+	}
+
+	return i.client.Do(req, nil)
+}
+
+type Transitions struct {
+	Expand      string       `json:"expand"`
+	Transitions []Transition `json:"transitions"`
+}
+
+type Transition struct {
+	ID            string                     `json:"id"`
+	Name          string                     `json:"name"`
+	To            Status                     `json:"to"`
+	HasScreen     bool                       `json:"hasScreen"`
+	IsGlobal      bool                       `json:"isGlobal"`
+	IsInitial     bool                       `json:"isInitial"`
+	IsAvailable   bool                       `json:"isAvailable"`
+	IsConditional bool                       `json:"isConditional"`
+	Fields        map[string]TransitionField `json:"fields"`
+}
+
+type TransitionField struct {
+	Required        bool   `json:"required"`
+	Name            string `json:"name"`
+	Key             string `json:"key"`
+	HasDefaultValue bool   `json:"hasDefaultValue"`
+}
+
+type transitionRequest struct {
+	Transition transitionID   `json:"transition"`
+	Fields     map[string]any `json:"fields,omitempty"`
+}
+
+type transitionID struct {
+	ID string `json:"id"`
+}
+
+// RequiredFields returns the sorted names of the fields that must be filled in
+// on the transition screen because they are required and have no default value.
+func (t *Transition) RequiredFields() []string {
+	var names []string
+	for id, f := range t.Fields {
+		if f.Required && !f.HasDefaultValue {
+			name := f.Name
+			if name == "" {
+				name = id
+			}
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// Validate returns a *TransitionFieldsError when the transition can not be
+// performed without filling in fields on its screen.
+func (t *Transition) Validate() error {
+	if fields := t.RequiredFields(); len(fields) > 0 {
+		return &TransitionFieldsError{Transition: t.Name, Fields: fields}
+	}
+
+	return nil
+}
+
+// TransitionFieldsError is returned when a transition requires fields to be set.
+type TransitionFieldsError struct {
+	Transition string
+	Fields     []string
+}
+
+func (e *TransitionFieldsError) Error() string {
+	return fmt.Sprintf(
+		"transition %q requires the following fields to be set: %s, use the Jira web interface instead",
+		e.Transition,
+		strings.Join(e.Fields, ", "),
+	)
+}
+
+// FindTransition returns the transition whose name or target status equals `name`,
+// ignoring case. Transition names take precedence over status names.
+func FindTransition(transitions []Transition, name string) (*Transition, error) {
+	for i := range transitions {
+		if strings.EqualFold(transitions[i].Name, name) {
+			return &transitions[i], nil
+		}
+	}
+
+	for i := range transitions {
+		if strings.EqualFold(transitions[i].To.Name, name) {
+			return &transitions[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrTransitionNotFound, name)
+}
@@ -0,0 +1,91 @@
+package jira_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MaikelVeen/branch/pkg/jira"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const transitionsResponse = `{
+	"transitions": [
+		{"id": "11", "name": "Start work", "to": {"name": "In Progress"}},
+		{"id": "21", "name": "Resolve", "to": {"name": "Done"}, "fields": {
+			"resolution": {"required": true, "name": "Resolution", "hasDefaultValue": false},
+			"comment": {"required": false, "name": "Comment"}
+		}}
+	]
+}`
+
+func TestTransitions(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rest/api/3/issue/TEST-1/transitions", r.URL.Path)
+
+		switch r.Method {
+		case http.MethodGet:
+			assert.Equal(t, "transitions.fields", r.URL.Query().Get("expand"))
+			_, _ = w.Write([]byte(transitionsResponse))
+		case http.MethodPost:
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			assert.JSONEq(t, `{"transition":{"id":"11"}}`, string(body))
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := jira.NewClient(srv.URL)
+	require.NoError(t, err)
+
+	transitions, err := client.Issue.GetTransitions(context.Background(), "TEST-1")
+	require.NoError(t, err)
+	require.Len(t, transitions, 2)
+
+	t.Run("find by transition name", func(t *testing.T) {
+		t.Parallel()
+
+		tr, err := jira.FindTransition(transitions, "start WORK")
+		require.NoError(t, err)
+		assert.Equal(t, "11", tr.ID)
+	})
+
+	t.Run("find by status name", func(t *testing.T) {
+		t.Parallel()
+
+		tr, err := jira.FindTransition(transitions, "in progress")
+		require.NoError(t, err)
+		assert.Equal(t, "11", tr.ID)
+		require.NoError(t, tr.Validate())
+	})
+
+	t.Run("unknown transition", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := jira.FindTransition(transitions, "Blocked")
+		require.ErrorIs(t, err, jira.ErrTransitionNotFound)
+	})
+
+	t.Run("required fields are reported", func(t *testing.T) {
+		t.Parallel()
+
+		tr, err := jira.FindTransition(transitions, "Done")
+		require.NoError(t, err)
+
+		var fieldsErr *jira.TransitionFieldsError
+		require.ErrorAs(t, tr.Validate(), &fieldsErr)
+		assert.Equal(t, []string{"Resolution"}, fieldsErr.Fields)
+	})
+
+	t.Run("do transition", func(t *testing.T) {
+		t.Parallel()
+
+		require.NoError(t, client.Issue.DoTransition(context.Background(), "TEST-1", "11", nil))
+	})
+}
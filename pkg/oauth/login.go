@@ -0,0 +1,129 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	readHeaderTimeout = 10 * time.Second
+)
+
+// Login runs the authorization code flow with PKCE. It starts a listener on the
+// loopback RedirectURL, calls `open` with the URL the user must visit to grant
+// access and waits until the authorization server redirects back, or `ctx` is done.
+func (c *Config) Login(ctx context.Context, open func(authURL string) error) (*Token, error) {
+	redirect, err := url.Parse(c.RedirectURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redirect URL: %w", err)
+	}
+
+	if !isLoopback(redirect.Hostname()) {
+		return nil, fmt.Errorf("redirect URL %s must point to localhost", c.RedirectURL)
+	}
+
+	var lc net.ListenConfig
+	listener, err := lc.Listen(ctx, "tcp", redirect.Host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for the OAuth callback: %w", err)
+	}
+
+	// Use the actual address in case a free port was requested.
+	redirect.Host = net.JoinHostPort(redirect.Hostname(), fmt.Sprint(listener.Addr().(*net.TCPAddr).Port))
+	redirectURL := redirect.String()
+
+	verifier, err := NewVerifier()
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := randomString(16)
+	if err != nil {
+		return nil, err
+	}
+
+	type result struct {
+		code string
+		err  error
+	}
+	results := make(chan result, 1)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(redirect.Path, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+
+		var res result
+		switch {
+		case q.Get("state") != state:
+			res.err = errors.New("oauth: state mismatch, the callback did not originate from this login")
+		case q.Get("error") != "":
+			res.err = &TokenError{Code: q.Get("error"), Description: q.Get("error_description")}
+		case q.Get("code") == "":
+			res.err = errors.New("oauth: callback is missing the authorization code")
+		default:
+			res.code = q.Get("code")
+		}
+
+		message := "Authorization complete, you can close this window."
+		if res.err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			message = "Authorization failed: " + html.EscapeString(res.err.Error())
+		}
+		fmt.Fprintf(w, "<html><body><p>%s</p></body></html>", message)
+
+		select {
+		case results <- res:
+		default:
+		}
+	})
+
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: readHeaderTimeout}
+	go func() { _ = srv.Serve(listener) }()
+	defer srv.Close()
+
+	if err = open(c.authCodeURL(state, Challenge(verifier), redirectURL)); err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-results:
+		if res.err != nil {
+			return nil, res.err
+		}
+		return c.Exchange(ctx, res.code, verifier, redirectURL)
+	}
+}
+
+// authCodeURL returns the URL of the consent screen.
+func (c *Config) authCodeURL(state, challenge, redirectURL string) string {
+	q := url.Values{}
+	q.Set("audience", "api.atlassian.com")
+	q.Set("client_id", c.ClientID)
+	q.Set("scope", strings.Join(c.Scopes, " "))
+	q.Set("redirect_uri", redirectURL)
+	q.Set("state", state)
+	q.Set("response_type", "code")
+	q.Set("prompt", "consent")
+	q.Set("code_challenge", challenge)
+	q.Set("code_challenge_method", "S256")
+
+	return c.AuthURL + "?" + q.Encode()
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
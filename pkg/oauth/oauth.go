@@ -0,0 +1,262 @@
+// Package oauth implements the OAuth 2.0 authorization code grant with PKCE
+// for Atlassian Cloud, also known as three-legged OAuth (3LO).
+//
+// https://developer.atlassian.com/cloud/jira/platform/oauth-2-3lo-apps/
+package oauth
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	DefaultAuthURL      = "https://auth.atlassian.com/authorize"
+	DefaultTokenURL     = "https://auth.atlassian.com/oauth/token"
+	DefaultResourcesURL = "https://api.atlassian.com/oauth/token/accessible-resources"
+	DefaultRedirectURL  = "http://localhost:8765/callback"
+
+	// APIBaseURLTemplate is the template for the base URL of the Jira API of a
+	// site, which must be used instead of the site URL with OAuth tokens.
+	APIBaseURLTemplate = "https://api.atlassian.com/ex/jira/%s"
+
+	// expiryDelta is subtracted from the expiry so tokens are refreshed before
+	// they expire while a request is in flight.
+	expiryDelta = time.Minute
+
+	defaultTimeout = 30 * time.Second
+)
+
+// DefaultScopes are the scopes needed by branch. The offline_access
+// scope is needed to receive a refresh token.
+var DefaultScopes = []string{"read:jira-user", "read:jira-work", "write:jira-work", "offline_access"}
+
+// Config describes an OAuth 2.0 app registered in the Atlassian developer console.
+type Config struct {
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+
+	// RedirectURL must be a loopback URL registered as callback URL of the app.
+	// A port of 0 picks a free port, which is useful for tests.
+	RedirectURL string
+
+	AuthURL      string
+	TokenURL     string
+	ResourcesURL string
+
+	// HTTPClient is used for requests to the token and resources endpoints.
+	HTTPClient *http.Client
+}
+
+// NewConfig returns a Config for the Atlassian authorization server.
+func NewConfig(clientID, clientSecret string) *Config {
+	return &Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Scopes:       DefaultScopes,
+		RedirectURL:  DefaultRedirectURL,
+		AuthURL:      DefaultAuthURL,
+		TokenURL:     DefaultTokenURL,
+		ResourcesURL: DefaultResourcesURL,
+	}
+}
+
+// Token holds the tokens issued by the authorization server.
+type Token struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	TokenType    string    `json:"token_type,omitempty"`
+	Scope        string    `json:"scope,omitempty"`
+	ExpiresIn    int       `json:"expires_in,omitempty"`
+	Expiry       time.Time `json:"expiry"`
+}
+
+// Valid reports whether the access token is present and not about to expire at `now`.
+func (t *Token) Valid(now time.Time) bool {
+	if t == nil || t.AccessToken == "" {
+		return false
+	}
+
+	return t.Expiry.IsZero() || now.Before(t.Expiry.Add(-expiryDelta))
+}
+
+// Resource is a site the token grants access to.
+type Resource struct {
+	ID        string   `json:"id"`
+	URL       string   `json:"url"`
+	Name      string   `json:"name"`
+	Scopes    []string `json:"scopes"`
+	AvatarURL string   `json:"avatarUrl"`
+}
+
+// TokenError is returned when the authorization server rejects a token request.
+type TokenError struct {
+	StatusCode  int
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e *TokenError) Error() string {
+	if e.Description != "" {
+		return fmt.Sprintf("oauth: %s: %s", e.Code, e.Description)
+	}
+
+	if e.Code != "" {
+		return fmt.Sprintf("oauth: %s", e.Code)
+	}
+
+	return fmt.Sprintf("oauth: unexpected status code %d", e.StatusCode)
+}
+
+// Exchange trades an authorization `code` for a token.
+func (c *Config) Exchange(ctx context.Context, code, verifier, redirectURL string) (*Token, error) {
+	return c.requestToken(ctx, map[string]string{
+		"grant_type":    "authorization_code",
+		"client_id":     c.ClientID,
+		"client_secret": c.ClientSecret,
+		"code":          code,
+		"redirect_uri":  redirectURL,
+		"code_verifier": verifier,
+	})
+}
+
+// Refresh obtains a new access token using `refreshToken`. Atlassian rotates
+// refresh tokens, so the returned token contains a new refresh token.
+func (c *Config) Refresh(ctx context.Context, refreshToken string) (*Token, error) {
+	if refreshToken == "" {
+		return nil, errors.New("oauth: no refresh token, log in again")
+	}
+
+	token, err := c.requestToken(ctx, map[string]string{
+		"grant_type":    "refresh_token",
+		"client_id":     c.ClientID,
+		"client_secret": c.ClientSecret,
+		"refresh_token": refreshToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if token.RefreshToken == "" {
+		token.RefreshToken = refreshToken
+	}
+
+	return token, nil
+}
+
+// AccessibleResources returns the sites that `token` grants access to. The
+// ID of a site is the cloud ID used in the API base URL.
+func (c *Config) AccessibleResources(ctx context.Context, token *Token) ([]Resource, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.ResourcesURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, fmt.Errorf("oauth: accessible resources returned status code %d", resp.StatusCode)
+	}
+
+	var resources []Resource
+	if err = json.NewDecoder(resp.Body).Decode(&resources); err != nil {
+		return nil, err
+	}
+
+	return resources, nil
+}
+
+func (c *Config) requestToken(ctx context.Context, params map[string]string) (*Token, error) {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.TokenURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		tokenErr := &TokenError{StatusCode: resp.StatusCode}
+		_ = json.Unmarshal(data, tokenErr)
+		return nil, tokenErr
+	}
+
+	token := new(Token)
+	if err = json.Unmarshal(data, token); err != nil {
+		return nil, err
+	}
+
+	if token.AccessToken == "" {
+		return nil, errors.New("oauth: server response is missing the access token")
+	}
+
+	if token.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+
+	return token, nil
+}
+
+func (c *Config) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+
+	return &http.Client{Timeout: defaultTimeout}
+}
+
+// NewVerifier returns a random PKCE code verifier.
+//
+// https://datatracker.ietf.org/doc/html/rfc7636#section-4.1
+func NewVerifier() (string, error) {
+	return randomString(32)
+}
+
+// Challenge returns the S256 code challenge for `verifier`.
+//
+// https://datatracker.ietf.org/doc/html/rfc7636#section-4.2
+func Challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,289 @@
+package oauth_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MaikelVeen/branch/pkg/oauth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeServer is a minimal authorization server. It approves every authorization
+// request, unless deny is set, and issues tokens for valid codes and refresh tokens.
+type fakeServer struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	deny       bool
+	challenges map[string]string
+	refreshes  int
+}
+
+func newFakeServer(t *testing.T) *fakeServer {
+	t.Helper()
+
+	fs := &fakeServer{challenges: map[string]string{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/authorize", fs.authorize)
+	mux.HandleFunc("/oauth/token", fs.token)
+	mux.HandleFunc("/accessible-resources", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`[{"id":"cloud-1","url":"https://acme.atlassian.net","name":"acme"}]`))
+	})
+
+	fs.Server = httptest.NewServer(mux)
+	t.Cleanup(fs.Close)
+
+	return fs
+}
+
+func (fs *fakeServer) config() *oauth.Config {
+	config := oauth.NewConfig("client", "secret")
+	config.RedirectURL = "http://127.0.0.1:0/callback"
+	config.AuthURL = fs.URL + "/authorize"
+	config.TokenURL = fs.URL + "/oauth/token"
+	config.ResourcesURL = fs.URL + "/accessible-resources"
+	return config
+}
+
+func (fs *fakeServer) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	redirect, _ := url.Parse(q.Get("redirect_uri"))
+
+	callback := url.Values{"state": {q.Get("state")}}
+	fs.mu.Lock()
+	switch {
+	case fs.deny:
+		callback.Set("error", "access_denied")
+	case q.Get("code_challenge_method") != "S256" || q.Get("client_id") != "client":
+		callback.Set("error", "invalid_request")
+	default:
+		callback.Set("code", "code-1")
+		fs.challenges["code-1"] = q.Get("code_challenge")
+	}
+	fs.mu.Unlock()
+
+	redirect.RawQuery = callback.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (fs *fakeServer) token(w http.ResponseWriter, r *http.Request) {
+	var req map[string]string
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req["client_secret"] != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":"access_denied","error_description":"Unauthorized"}`))
+		return
+	}
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	switch req["grant_type"] {
+	case "authorization_code":
+		challenge, ok := fs.challenges[req["code"]]
+		if !ok || oauth.Challenge(req["code_verifier"]) != challenge {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant","error_description":"Invalid authorization code"}`))
+			return
+		}
+		delete(fs.challenges, req["code"])
+		_, _ = w.Write([]byte(`{"access_token":"access-1","refresh_token":"refresh-1","expires_in":3600}`))
+	case "refresh_token":
+		if req["refresh_token"] != fmt.Sprintf("refresh-%d", fs.refreshes+1) {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant","error_description":"Unknown or invalid refresh token."}`))
+			return
+		}
+		fs.refreshes++
+		fmt.Fprintf(w, `{"access_token":"access-%d","refresh_token":"refresh-%d","expires_in":3600}`,
+			fs.refreshes+1, fs.refreshes+1)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+// visit follows the authorization URL like a browser would.
+func visit(authURL string) error {
+	resp, err := http.Get(authURL) //nolint:noctx // test helper following redirects like a browser.
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+func TestChallenge(t *testing.T) {
+	t.Parallel()
+
+	// BASE64URL(SHA256("verifier")) without padding.
+	assert.Equal(t, "iMnq5o6zALKXGivsnlom_0F5_WYda32GHkxlV7mq7hQ", oauth.Challenge("verifier"))
+
+	v1, err := oauth.NewVerifier()
+	require.NoError(t, err)
+	v2, err := oauth.NewVerifier()
+	require.NoError(t, err)
+	assert.NotEqual(t, v1, v2)
+	assert.GreaterOrEqual(t, len(v1), 43)
+}
+
+func TestLogin(t *testing.T) {
+	t.Parallel()
+
+	fs := newFakeServer(t)
+	config := fs.config()
+
+	token, err := config.Login(context.Background(), visit)
+	require.NoError(t, err)
+	assert.Equal(t, "access-1", token.AccessToken)
+	assert.Equal(t, "refresh-1", token.RefreshToken)
+	assert.True(t, token.Valid(time.Now()))
+	assert.False(t, token.Valid(time.Now().Add(time.Hour)))
+
+	resources, err := config.AccessibleResources(context.Background(), token)
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+	assert.Equal(t, "cloud-1", resources[0].ID)
+}
+
+func TestLoginDenied(t *testing.T) {
+	t.Parallel()
+
+	fs := newFakeServer(t)
+	fs.deny = true
+
+	_, err := fs.config().Login(context.Background(), visit)
+
+	var tokenErr *oauth.TokenError
+	require.ErrorAs(t, err, &tokenErr)
+	assert.Equal(t, "access_denied", tokenErr.Code)
+}
+
+func TestLoginRejectsForeignRedirect(t *testing.T) {
+	t.Parallel()
+
+	config := oauth.NewConfig("client", "secret")
+	config.RedirectURL = "https://example.com/callback"
+
+	_, err := config.Login(context.Background(), visit)
+	require.ErrorContains(t, err, "must point to localhost")
+}
+
+func TestLoginCanceled(t *testing.T) {
+	t.Parallel()
+
+	fs := newFakeServer(t)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	_, err := fs.config().Login(ctx, func(string) error {
+		cancel()
+		return nil
+	})
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestTransportRefresh(t *testing.T) {
+	t.Parallel()
+
+	fs := newFakeServer(t)
+
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get("Authorization")))
+	}))
+	t.Cleanup(api.Close)
+
+	var saved []*oauth.Token
+	expired := &oauth.Token{AccessToken: "access-1", RefreshToken: "refresh-1", Expiry: time.Now().Add(-time.Minute)}
+	transport := oauth.NewTransport(fs.config(), expired, func(token *oauth.Token) error {
+		saved = append(saved, token)
+		return nil
+	})
+	client := &http.Client{Transport: transport}
+
+	get := func() string {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, api.URL, nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	// The expired token is refreshed once and the rotated refresh token is kept.
+	assert.Equal(t, "Bearer access-2", get())
+	assert.Equal(t, "Bearer access-2", get())
+	require.Len(t, saved, 1)
+	assert.Equal(t, "refresh-2", saved[0].RefreshToken)
+}
+
+func TestTransportRefreshSaveFailure(t *testing.T) {
+	t.Parallel()
+
+	fs := newFakeServer(t)
+
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get("Authorization")))
+	}))
+	t.Cleanup(api.Close)
+
+	var log bytes.Buffer
+	expired := &oauth.Token{AccessToken: "access-1", RefreshToken: "refresh-1", Expiry: time.Now().Add(-time.Minute)}
+	transport := oauth.NewTransport(fs.config(), expired, func(*oauth.Token) error {
+		return errors.New("failed to save the refreshed token")
+	})
+	transport.Logger = slog.New(slog.NewTextHandler(&log, nil))
+	client := &http.Client{Transport: transport}
+
+	get := func() string {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, api.URL, nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err, "a token that can not be saved does not fail the request")
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	// The refreshed token is kept, as the old refresh token is rotated.
+	assert.Equal(t, "Bearer access-2", get())
+	assert.Equal(t, "Bearer access-2", get())
+	assert.Contains(t, log.String(), "failed to save the refreshed token")
+}
+
+func TestTransportRefreshRejected(t *testing.T) {
+	t.Parallel()
+
+	fs := newFakeServer(t)
+	expired := &oauth.Token{AccessToken: "access-1", RefreshToken: "revoked", Expiry: time.Now().Add(-time.Minute)}
+	client := &http.Client{Transport: oauth.NewTransport(fs.config(), expired, nil)}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, fs.URL, nil)
+	require.NoError(t, err)
+
+	resp, err := client.Do(req)
+	if resp != nil {
+		resp.Body.Close()
+	}
+
+	var tokenErr *oauth.TokenError
+	require.ErrorAs(t, err, &tokenErr)
+	assert.Equal(t, "invalid_grant", tokenErr.Code)
+}
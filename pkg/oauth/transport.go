@@ -0,0 +1,94 @@
+package oauth
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Transport is an http.RoundTripper that authenticates requests with an access
+// token, refreshing it when it is about to expire.
+type Transport struct {
+	// Base is the underlying transport, http.DefaultTransport when nil.
+	Base http.RoundTripper
+	// Config is used to refresh the token.
+	Config *Config
+	// OnRefresh is called with the new token after a refresh, so it can be persisted.
+	OnRefresh func(*Token) error
+	// Logger reports errors of OnRefresh, slog.Default() when nil.
+	Logger *slog.Logger
+
+	mu    sync.Mutex
+	token *Token
+}
+
+// NewTransport returns a transport that starts out with `token`.
+func NewTransport(config *Config, token *Token, onRefresh func(*Token) error) *Transport {
+	return &Transport{Config: config, OnRefresh: onRefresh, token: token}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.Token(req)
+	if err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+
+	// A RoundTripper must not modify the request it was given.
+	clone := req.Clone(req.Context())
+	clone.Header.Set("Authorization", "Bearer "+token.AccessToken)
+
+	return t.base().RoundTrip(clone)
+}
+
+// Token returns a valid token, refreshing the current one if needed.
+func (t *Transport) Token(req *http.Request) (*Token, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token.Valid(time.Now()) {
+		return t.token, nil
+	}
+
+	var refreshToken string
+	if t.token != nil {
+		refreshToken = t.token.RefreshToken
+	}
+
+	token, err := t.Config.Refresh(req.Context(), refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// The refresh token is rotated, so the new token is used even when it can
+	// not be persisted. Failing the request would lose it altogether.
+	t.token = token
+	if t.OnRefresh != nil {
+		if err = t.OnRefresh(token); err != nil {
+			t.logger().Warn(fmt.Sprintf("%s, the refreshed token is only used until the command exits", err))
+		}
+	}
+
+	return token, nil
+}
+
+func (t *Transport) logger() *slog.Logger {
+	if t.Logger != nil {
+		return t.Logger
+	}
+
+	return slog.Default()
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+
+	return http.DefaultTransport
+}